@@ -14,27 +14,33 @@ func NewJSONLogger(options ...Option) *slog.Logger {
 	opts := mapOptionsToDefaults(options)
 
 	jsonHandler := slog.NewJSONHandler(opts.writer, &slog.HandlerOptions{
-		AddSource: opts.addSource,
-		Level:     opts.level,
-		ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
-			if opts.now != nil && attr.Key == slog.TimeKey {
-				attr.Value = slog.TimeValue(opts.now())
-			}
-
-			return attr
-		},
+		AddSource:   opts.addSource,
+		Level:       opts.level,
+		ReplaceAttr: opts.replaceAttrFunc(),
 	})
 
-	return slog.New(slogctx.NewHandler(jsonHandler))
+	return slog.New(slogctx.NewHandler(jsonHandler, opts.handlerOptions...))
 }
 
 // NewInMemoryLogger creates a new [slog.Logger] configured with a
-// [slogmem.Handler] to capture logged records in-memory for testing.
+// [slogctx.Handler] which wraps a [slogmem.Handler] to capture logged records
+// in-memory for testing.
+//
+// The same [Option] values accepted by [NewJSONLogger] are applied to the
+// [slogmem.Handler] so that the logger mirrors the production configuration,
+// except for [WithLevel], which is replaced by the given level, and
+// [WithWriter], which is ignored as records are captured in-memory. Use
+// [WithInMemoryOptions] to configure the [slogmem.Handler] further.
 //
 // A [slogmem.LoggedRecords] will also be returned containing the
 // records created by the returned [slog.Logger].
-func NewInMemoryLogger(level slog.Leveler) (*slog.Logger, *slogmem.LoggedRecords) {
-	handler := slogmem.NewHandler(level)
+func NewInMemoryLogger(level slog.Leveler, options ...Option) (*slog.Logger, *slogmem.LoggedRecords) {
+	opts := mapOptionsToDefaults(options)
+
+	handler := slogmem.NewHandler(level, append([]slogmem.Option{
+		slogmem.WithSourceAdded(opts.addSource),
+		slogmem.WithReplaceAttr(opts.replaceAttrFunc()),
+	}, opts.inMemoryOptions...)...)
 
-	return slog.New(slogctx.NewHandler(handler)), handler.Records()
+	return slog.New(slogctx.NewHandler(handler, opts.handlerOptions...)), handler.Records()
 }
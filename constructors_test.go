@@ -0,0 +1,141 @@
+package slogutil_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/nickbryan/slogutil"
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestNewInMemoryLoggerAppliesOptions(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		level   slog.Leveler
+		options []slogutil.Option
+		log     func(logger *slog.Logger)
+		assert  func(t *testing.T, logs *slogmem.LoggedRecords)
+	}{
+		"applies the level": {
+			level:   slog.LevelWarn,
+			options: nil,
+			log: func(logger *slog.Logger) {
+				logger.InfoContext(context.Background(), "Info log message")
+				logger.WarnContext(context.Background(), "Warn log message")
+			},
+			assert: func(t *testing.T, logs *slogmem.LoggedRecords) {
+				t.Helper()
+
+				if got := logs.Len(); got != 1 {
+					t.Errorf("logs.Len() want: 1, got: %d", got)
+				}
+			},
+		},
+		"ignores the level option": {
+			level:   slog.LevelInfo,
+			options: []slogutil.Option{slogutil.WithLevel(slog.LevelWarn)},
+			log: func(logger *slog.Logger) {
+				logger.InfoContext(context.Background(), "Info log message")
+			},
+			assert: func(t *testing.T, logs *slogmem.LoggedRecords) {
+				t.Helper()
+
+				if got := logs.Len(); got != 1 {
+					t.Errorf("logs.Len() want: 1, got: %d", got)
+				}
+			},
+		},
+		"applies the time factory": {
+			level:   slog.LevelInfo,
+			options: []slogutil.Option{slogutil.WithTimeFactory(constantTimeFactory)},
+			log: func(logger *slog.Logger) {
+				logger.InfoContext(context.Background(), "Info log message")
+			},
+			assert: func(t *testing.T, logs *slogmem.LoggedRecords) {
+				t.Helper()
+
+				for record := range logs.All() {
+					if !record.Time.Equal(constantTimeFactory()) {
+						t.Errorf("record.Time want: %s, got: %s", constantTimeFactory(), record.Time)
+					}
+				}
+			},
+		},
+		"does not add the source when disabled": {
+			level:   slog.LevelInfo,
+			options: []slogutil.Option{slogutil.WithSourceAdded(false)},
+			log: func(logger *slog.Logger) {
+				logger.InfoContext(context.Background(), "Info log message")
+			},
+			assert: func(t *testing.T, logs *slogmem.LoggedRecords) {
+				t.Helper()
+
+				for record := range logs.All() {
+					if record.Source != nil {
+						t.Errorf("record.Source want: nil, got: %+v", record.Source)
+					}
+				}
+			},
+		},
+		"applies the replace attr function to grouped attrs": {
+			level: slog.LevelInfo,
+			options: []slogutil.Option{slogutil.WithReplaceAttr(func(groups []string, attr slog.Attr) slog.Attr {
+				if len(groups) == 1 && groups[0] == "g1" && attr.Key == "secret" {
+					return slog.Attr{}
+				}
+
+				return attr
+			})},
+			log: func(logger *slog.Logger) {
+				logger.WithGroup("g1").InfoContext(context.Background(), "Info log message", slog.String("secret", "value"), slog.String("public", "value"))
+			},
+			assert: func(t *testing.T, logs *slogmem.LoggedRecords) {
+				t.Helper()
+
+				query := slogmem.RecordQuery{
+					Level:   slog.LevelInfo,
+					Message: "Info log message",
+					Attrs:   map[string]slog.Value{"g1.public": slog.StringValue("value")},
+				}
+
+				if ok, diff := logs.ContainsExact(query); !ok {
+					t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+				}
+			},
+		},
+		"applies the handler options": {
+			level:   slog.LevelInfo,
+			options: []slogutil.Option{slogutil.WithHandlerOptions(slogctx.WithMergedGroups(true))},
+			log: func(logger *slog.Logger) {
+				logger.With(slog.Group("http", slog.String("method", "GET"))).WithGroup("http").InfoContext(context.Background(), "Info log message", slog.Int("status", 200))
+			},
+			assert: func(t *testing.T, logs *slogmem.LoggedRecords) {
+				t.Helper()
+
+				query := slogmem.RecordQuery{
+					Level:   slog.LevelInfo,
+					Message: "Info log message",
+					Attrs:   map[string]slog.Value{"http.method": slog.StringValue("GET"), "http.status": slog.IntValue(200)},
+				}
+
+				if ok, diff := logs.ContainsExact(query); !ok {
+					t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+				}
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			logger, logs := slogutil.NewInMemoryLogger(tc.level, tc.options...)
+
+			tc.log(logger)
+			tc.assert(t, logs)
+		})
+	}
+}
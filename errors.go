@@ -0,0 +1,195 @@
+package slogutil
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+)
+
+// ErrorKey is the key used by [ErrorAttr] for the error attribute.
+const ErrorKey = "error"
+
+const (
+	// maxErrorDepth bounds how deep the chain of wrapped errors is rendered to
+	// protect against errors that wrap themselves.
+	maxErrorDepth = 16
+	// maxStackDepth is the maximum number of frames captured by [WithStack].
+	maxStackDepth = 32
+)
+
+type (
+	// stackTracer is implemented by errors that carry the program counters of
+	// the stack where they were created, such as those returned by [WithStack].
+	stackTracer interface {
+		Callers() []uintptr
+	}
+
+	// stackError wraps an error with the stack where it was wrapped.
+	stackError struct {
+		err     error
+		callers []uintptr
+	}
+
+	// errorValuer renders an error as a structured group when resolved.
+	errorValuer struct {
+		err error
+	}
+)
+
+// WithStack wraps err with the stack of the caller so that the stack is
+// rendered by [ErrorValue]. The wrapped error can be unwrapped with
+// [errors.Unwrap] and behaves as err with [errors.Is] and [errors.As]. A nil err
+// returns nil.
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	const skip = 2 // runtime.Callers and WithStack.
+
+	callers := make([]uintptr, maxStackDepth)
+
+	return &stackError{err: err, callers: callers[:runtime.Callers(skip, callers)]}
+}
+
+// Ensure that our [stackError] implements the stackTracer interface.
+var _ stackTracer = &stackError{} //nolint:exhaustruct // Compile time implementation check.
+
+// Error returns the message of the wrapped error.
+func (e *stackError) Error() string { return e.err.Error() }
+
+// Unwrap returns the wrapped error.
+func (e *stackError) Unwrap() error { return e.err }
+
+// Callers returns the program counters of the stack where the error was wrapped.
+func (e *stackError) Callers() []uintptr { return e.callers }
+
+// ErrorAttr returns an attr with the [ErrorKey] key that renders err as a
+// structured group, see [ErrorValue]. A nil err returns an empty attr, which is
+// ignored by handlers.
+func ErrorAttr(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+
+	return slog.Any(ErrorKey, errorValuer{err: err})
+}
+
+// ErrorValue renders err as a group containing the following attrs:
+//
+//   - msg: the error message.
+//   - type: the Go type of the error.
+//   - stack: the stack where the error was wrapped by [WithStack], or any
+//     other error with a Callers() []uintptr method, as a list of frames.
+//   - cause: the error returned by Unwrap() error, rendered in the same way.
+//   - errors: a group of the errors returned by Unwrap() []error, such as from
+//     [errors.Join], keyed by their index and rendered in the same way.
+//
+// Errors wrapped by [WithStack] are rendered as the error that they wrap with
+// the stack added. A nil err is rendered as an empty group, which is ignored by
+// handlers.
+func ErrorValue(err error) slog.Value {
+	if err == nil {
+		return slog.GroupValue()
+	}
+
+	return errorGroupValue(err, 0)
+}
+
+// LogValue implements the [slog.LogValuer] interface.
+func (ev errorValuer) LogValue() slog.Value {
+	return ErrorValue(ev.err)
+}
+
+// ReplaceErrorAttr can be used as, or called from, a
+// [slog.HandlerOptions.ReplaceAttr] function to render each attr whose value is
+// an error as a structured group, see [ErrorValue]. All other attrs are
+// returned unchanged.
+func ReplaceErrorAttr(_ []string, attr slog.Attr) slog.Attr {
+	if attr.Value.Kind() != slog.KindAny {
+		return attr
+	}
+
+	if err, ok := attr.Value.Any().(error); ok && err != nil {
+		attr.Value = ErrorValue(err)
+	}
+
+	return attr
+}
+
+// errorGroupValue renders err and the errors that it wraps up to maxErrorDepth.
+func errorGroupValue(err error, depth int) slog.Value {
+	var callers []uintptr
+
+	for {
+		stackErr, ok := err.(*stackError) //nolint:errorlint // Only the outermost wrappers are unwrapped.
+		if !ok {
+			break
+		}
+
+		if callers == nil {
+			callers = stackErr.callers
+		}
+
+		err = stackErr.err
+	}
+
+	if tracer, ok := err.(stackTracer); ok && callers == nil { //nolint:errorlint // Stacks are rendered at the level that they are found.
+		callers = tracer.Callers()
+	}
+
+	attrs := []slog.Attr{
+		slog.String("msg", err.Error()),
+		slog.String("type", fmt.Sprintf("%T", err)),
+	}
+
+	if len(callers) > 0 {
+		attrs = append(attrs, slog.Any("stack", stackFrames(callers)))
+	}
+
+	if depth >= maxErrorDepth {
+		return slog.GroupValue(attrs...)
+	}
+
+	switch wrapper := err.(type) { //nolint:errorlint // The direct wrapper methods are required to walk the chain.
+	case interface{ Unwrap() error }:
+		if cause := wrapper.Unwrap(); cause != nil {
+			attrs = append(attrs, slog.Attr{Key: "cause", Value: errorGroupValue(cause, depth+1)})
+		}
+	case interface{ Unwrap() []error }:
+		joined := make([]slog.Attr, 0)
+
+		for i, joinedErr := range wrapper.Unwrap() {
+			if joinedErr != nil {
+				joined = append(joined, slog.Attr{Key: strconv.Itoa(i), Value: errorGroupValue(joinedErr, depth+1)})
+			}
+		}
+
+		if len(joined) > 0 {
+			attrs = append(attrs, slog.Attr{Key: "errors", Value: slog.GroupValue(joined...)})
+		}
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// stackFrames formats the program counters as a list of "function file:line"
+// frames.
+func stackFrames(callers []uintptr) []string {
+	frames := runtime.CallersFrames(callers)
+	formatted := make([]string, 0, len(callers))
+
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			formatted = append(formatted, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return formatted
+}
@@ -0,0 +1,177 @@
+package slogutil_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nickbryan/slogutil"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestErrorAttr(t *testing.T) {
+	t.Parallel()
+
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	testCases := map[string]struct {
+		err  error
+		want map[string]slog.Value
+	}{
+		"renders a plain error": {
+			err: errFirst,
+			want: map[string]slog.Value{
+				"error.msg":  slog.StringValue("first"),
+				"error.type": slog.StringValue("*errors.errorString"),
+			},
+		},
+		"renders the chain of a wrapped error": {
+			err: fmt.Errorf("wrapping: %w", errFirst),
+			want: map[string]slog.Value{
+				"error.msg":        slog.StringValue("wrapping: first"),
+				"error.type":       slog.StringValue("*fmt.wrapError"),
+				"error.cause.msg":  slog.StringValue("first"),
+				"error.cause.type": slog.StringValue("*errors.errorString"),
+			},
+		},
+		"renders each error of a joined error": {
+			err: errors.Join(errFirst, errSecond),
+			want: map[string]slog.Value{
+				"error.msg":           slog.StringValue("first\nsecond"),
+				"error.type":          slog.StringValue("*errors.joinError"),
+				"error.errors.0.msg":  slog.StringValue("first"),
+				"error.errors.0.type": slog.StringValue("*errors.errorString"),
+				"error.errors.1.msg":  slog.StringValue("second"),
+				"error.errors.1.type": slog.StringValue("*errors.errorString"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo)
+
+			logger.ErrorContext(context.Background(), "Error log message", slogutil.ErrorAttr(tc.err))
+
+			query := slogmem.RecordQuery{Level: slog.LevelError, Message: "Error log message", Attrs: tc.want}
+			if ok, diff := logs.ContainsExact(query); !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+			}
+		})
+	}
+}
+
+func TestErrorAttrIgnoresNilErrors(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo)
+
+	logger.ErrorContext(context.Background(), "Error log message", slogutil.ErrorAttr(nil))
+
+	query := slogmem.RecordQuery{Level: slog.LevelError, Message: "Error log message", Attrs: map[string]slog.Value{}}
+	if ok, diff := logs.ContainsExact(query); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+	}
+}
+
+func TestWithStack(t *testing.T) {
+	t.Parallel()
+
+	err := slogutil.WithStack(fmt.Errorf("reading config: %w", fs.ErrNotExist))
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("errors.Is(err, fs.ErrNotExist) want: true, got: false")
+	}
+
+	if slogutil.WithStack(nil) != nil {
+		t.Errorf("slogutil.WithStack(nil) want: nil, got: non-nil")
+	}
+
+	logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo)
+	logger.ErrorContext(context.Background(), "Error log message", slogutil.ErrorAttr(err))
+
+	for record := range logs.All() {
+		if msg, _ := record.Attr("error.msg"); msg.String() != "reading config: file does not exist" {
+			t.Errorf("error.msg want: the message of the wrapped error, got: %s", msg)
+		}
+
+		if typ, _ := record.Attr("error.type"); typ.String() != "*fmt.wrapError" {
+			t.Errorf("error.type want: the type of the wrapped error, got: %s", typ)
+		}
+
+		stack, ok := record.Attr("error.stack")
+		if !ok {
+			t.Fatal("expected the error to have a stack")
+		}
+
+		frames, _ := stack.Any().([]string)
+		if len(frames) == 0 || !strings.HasPrefix(frames[0], "github.com/nickbryan/slogutil_test.TestWithStack ") {
+			t.Errorf("expected the first frame to be the caller of WithStack, got: %v", frames)
+		}
+	}
+}
+
+func TestWithStructuredErrors(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("wrapping: %w", errors.New("cause"))
+
+	t.Run("renders error attrs as groups in the in-memory logger", func(t *testing.T) {
+		t.Parallel()
+
+		logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo, slogutil.WithStructuredErrors(true))
+
+		logger.WithGroup("g1").ErrorContext(context.Background(), "Error log message", slog.Any("err", err), slog.String("other", "value"))
+
+		query := slogmem.RecordQuery{
+			Level:   slog.LevelError,
+			Message: "Error log message",
+			Attrs: map[string]slog.Value{
+				"g1.err.msg":       slog.StringValue("wrapping: cause"),
+				"g1.err.cause.msg": slog.StringValue("cause"),
+				"g1.other":         slog.StringValue("value"),
+			},
+		}
+
+		if ok, diff := logs.Contains(query); !ok {
+			t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+		}
+	})
+
+	t.Run("renders error attrs as objects in the JSON logger", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		logger := slogutil.NewJSONLogger(slogutil.WithWriter(&buf), slogutil.WithStructuredErrors(true))
+
+		logger.ErrorContext(context.Background(), "Error log message", slog.Any("err", err))
+
+		var got struct {
+			Err struct {
+				Msg   string `json:"msg"`
+				Type  string `json:"type"`
+				Cause struct {
+					Msg string `json:"msg"`
+				} `json:"cause"`
+			} `json:"err"`
+		}
+
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("unmarshalling log line: %v", err)
+		}
+
+		if got.Err.Msg != "wrapping: cause" || got.Err.Type != "*fmt.wrapError" || got.Err.Cause.Msg != "cause" {
+			t.Errorf("expected the error to be rendered as an object, got: %s", buf.String())
+		}
+	})
+}
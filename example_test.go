@@ -90,3 +90,32 @@ func ExampleNewInMemoryLogger() {
 
 	// Output: Record contains query
 }
+
+func ExampleNewInMemoryLogger_options() {
+	logger, logs := slogutil.NewInMemoryLogger(
+		slog.LevelDebug,
+		slogutil.WithReplaceAttr(func(_ []string, attr slog.Attr) slog.Attr {
+			if attr.Key == "password" {
+				return slog.String(attr.Key, "REDACTED")
+			}
+
+			return attr
+		}),
+		slogutil.WithInMemoryOptions(slogmem.WithCapacity(1)),
+	)
+
+	logger.DebugContext(context.Background(), "Evicted log message")
+	logger.InfoContext(context.Background(), "User logged in", slog.String("password", "hunter2"))
+
+	if ok, diff := logs.ContainsExact(slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "User logged in",
+		Attrs:   map[string]slog.Value{"password": slog.StringValue("REDACTED")},
+	}); !ok {
+		fmt.Print(diff)
+	} else {
+		fmt.Printf("Record contains query, %d record evicted", logs.Evicted())
+	}
+
+	// Output: Record contains query, 1 record evicted
+}
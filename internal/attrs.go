@@ -4,6 +4,7 @@ package internal
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 )
 
@@ -102,6 +103,51 @@ func (agh *AttrGroupHistory) PushFront(attrs []slog.Attr) {
 	agh.groups[0].attrs = append(attrs, agh.groups[0].attrs...)
 }
 
+// PushGroup adds the given attrs to the group at the given path from the root
+// of the [AttrGroupHistory]. Each level of the path is merged into the next
+// descendant group or an attr group within the current group when either has
+// the same name, otherwise the remaining levels are added as nested groups
+// after the attrs of the current group. An empty path adds the attrs to the
+// root group.
+func (agh *AttrGroupHistory) PushGroup(path []string, attrs []slog.Attr) {
+	if len(agh.groups) == 0 || len(attrs) == 0 {
+		return
+	}
+
+	level := 0
+	for len(path) > 0 && level+1 < len(agh.groups) && agh.groups[level+1].name == path[0] {
+		level++
+		path = path[1:]
+	}
+
+	agh.groups[level].attrs = pushGroupAttrs(agh.groups[level].attrs, path, attrs)
+}
+
+// pushGroupAttrs returns a copy of groupAttrs with the attrs added to the
+// group attr at the given path, creating any groups in the path that do not
+// exist at the end of groupAttrs.
+func pushGroupAttrs(groupAttrs []slog.Attr, path []string, attrs []slog.Attr) []slog.Attr {
+	if len(path) == 0 {
+		return append(slices.Clip(groupAttrs), attrs...)
+	}
+
+	for i, attr := range groupAttrs {
+		if attr.Key == path[0] && attr.Value.Kind() == slog.KindGroup {
+			merged := slices.Clone(groupAttrs)
+			merged[i].Value = slog.GroupValue(pushGroupAttrs(attr.Value.Group(), path[1:], attrs)...)
+
+			return merged
+		}
+	}
+
+	nested := attrs
+	for i := len(path) - 1; i > 0; i-- {
+		nested = []slog.Attr{{Key: path[i], Value: slog.GroupValue(nested...)}}
+	}
+
+	return append(slices.Clip(groupAttrs), slog.Attr{Key: path[0], Value: slog.GroupValue(nested...)})
+}
+
 // DeduplicatedAttrs returns the flattened slice of [slog.Attr] with attrs
 // properly nested within the desired groups. Where there are multiple attrs at
 // the same group level with the same key, the first attr's key will be left as is
@@ -111,6 +157,92 @@ func (agh *AttrGroupHistory) DeduplicatedAttrs() []slog.Attr {
 	return agh.resolve()
 }
 
+// MergedAttrs returns the flattened slice of [slog.Attr] with attrs properly
+// nested within the desired groups in the same way as [AttrGroupHistory.DeduplicatedAttrs],
+// except that groups with the same key at the same group level are merged into
+// a single group rather than being suffixed with #0x. Duplicate attr keys
+// within the merged groups are still suffixed.
+func (agh *AttrGroupHistory) MergedAttrs() []slog.Attr {
+	if len(agh.groups) == 0 {
+		return nil
+	}
+
+	merged := &AttrGroupHistory{
+		groups:            []AttrGroup{{name: "", path: "", attrs: mergeGroups(agh.nest())}},
+		duplicateAttrKeys: agh.duplicateAttrKeys,
+	}
+
+	return merged.resolve()
+}
+
+// nest returns the attrs of the [AttrGroupHistory] with each descendant group
+// nested as a group attr at the end of the attrs of its ancestor.
+func (agh *AttrGroupHistory) nest() []slog.Attr {
+	var attrs []slog.Attr
+
+	for i := len(agh.groups) - 1; i >= 0; i-- {
+		groupAttrs := slices.Clone(agh.groups[i].attrs)
+		if len(attrs) > 0 {
+			groupAttrs = append(groupAttrs, slog.Attr{Key: agh.groups[i+1].name, Value: slog.GroupValue(attrs...)})
+		}
+
+		attrs = groupAttrs
+	}
+
+	return attrs
+}
+
+// mergeGroups resolves the values of the attrs, inlines groups without a key
+// and combines the members of groups with the same key into the first of those
+// groups, recursively.
+func mergeGroups(attrs []slog.Attr) []slog.Attr {
+	merged := make([]slog.Attr, 0, len(attrs))
+	groupIndexes := make(map[string]int)
+
+	for _, attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+
+		if attr.Value.Kind() != slog.KindGroup {
+			merged = append(merged, attr)
+			continue
+		}
+
+		if attr.Key == "" {
+			for _, inlined := range mergeGroups(attr.Value.Group()) {
+				merged = mergeInto(merged, groupIndexes, inlined)
+			}
+
+			continue
+		}
+
+		merged = mergeInto(merged, groupIndexes, attr)
+	}
+
+	for _, i := range groupIndexes {
+		merged[i].Value = slog.GroupValue(mergeGroups(merged[i].Value.Group())...)
+	}
+
+	return merged
+}
+
+// mergeInto appends the attr to merged, combining it with an existing group of
+// the same key when it is a group.
+func mergeInto(merged []slog.Attr, groupIndexes map[string]int, attr slog.Attr) []slog.Attr {
+	if attr.Value.Kind() != slog.KindGroup {
+		return append(merged, attr)
+	}
+
+	i, ok := groupIndexes[attr.Key]
+	if !ok {
+		groupIndexes[attr.Key] = len(merged)
+		return append(merged, attr)
+	}
+
+	merged[i].Value = slog.GroupValue(append(slices.Clip(merged[i].Value.Group()), attr.Value.Group()...)...)
+
+	return merged
+}
+
 // resolve returns the [AttrGroupHistory] as a flattened slice of resolved
 // [slog.Attr] values, qualified by all applicable group names and ready for a
 // [slog.Handler].
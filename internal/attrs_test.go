@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 
 	"github.com/nickbryan/slogutil/internal"
 )
@@ -312,3 +313,156 @@ func TestAttrGroupHistoryPushFront(t *testing.T) {
 		})
 	}
 }
+
+func TestAttrGroupHistoryPushGroup(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		attrGroupHistory *internal.AttrGroupHistory
+		path             []string
+		pushAttrs        []slog.Attr
+		want             []slog.Attr
+	}{
+		"calling PushGroup with empty attrs does nothing": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithAttrs([]slog.Attr{slog.String("aK", "aV")}).History(),
+			path:             []string{"g1"},
+			pushAttrs:        nil,
+			want:             []slog.Attr{slog.String("aK", "aV")},
+		},
+		"calling PushGroup with an empty name adds the attrs to the end of the root group": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithAttrs([]slog.Attr{slog.String("aK", "aV")}).WithGroup("g1").WithAttrs([]slog.Attr{slog.Int("g1K", 123)}).History(),
+			path:             nil,
+			pushAttrs:        []slog.Attr{slog.String("bK", "bV")},
+			want:             []slog.Attr{slog.String("aK", "aV"), slog.String("bK", "bV"), slog.Group("g1", slog.Int("g1K", 123))},
+		},
+		"calling PushGroup adds a new group after the attrs of the root group": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithAttrs([]slog.Attr{slog.String("aK", "aV")}).WithGroup("g1").WithAttrs([]slog.Attr{slog.Int("g1K", 123)}).History(),
+			path:             []string{"g2"},
+			pushAttrs:        []slog.Attr{slog.String("bK", "bV")},
+			want: []slog.Attr{
+				slog.String("aK", "aV"),
+				slog.Group("g2", slog.String("bK", "bV")),
+				slog.Group("g1", slog.Int("g1K", 123)),
+			},
+		},
+		"calling PushGroup with the name of the first descendant group merges the attrs into the group": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithGroup("g1").WithAttrs([]slog.Attr{slog.String("aK", "aV")}).WithGroup("g2").History(),
+			path:             []string{"g1"},
+			pushAttrs:        []slog.Attr{slog.String("aK", "bV")},
+			want:             []slog.Attr{slog.Group("g1", slog.String("aK", "aV"), slog.String("aK#01", "bV"))},
+		},
+		"calling PushGroup with the name of a group attr at the root merges the attrs into the group": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithAttrs([]slog.Attr{slog.Group("g1", slog.String("aK", "aV")), slog.String("bK", "bV")}).History(),
+			path:             []string{"g1"},
+			pushAttrs:        []slog.Attr{slog.String("cK", "cV")},
+			want:             []slog.Attr{slog.Group("g1", slog.String("aK", "aV"), slog.String("cK", "cV")), slog.String("bK", "bV")},
+		},
+		"calling PushGroup with a path merges each level into the descendant groups": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithGroup("g1").WithAttrs([]slog.Attr{slog.String("aK", "aV")}).WithGroup("g2").WithAttrs([]slog.Attr{slog.String("bK", "bV")}).History(),
+			path:             []string{"g1", "g2"},
+			pushAttrs:        []slog.Attr{slog.String("cK", "cV")},
+			want:             []slog.Attr{slog.Group("g1", slog.String("aK", "aV"), slog.Group("g2", slog.String("bK", "bV"), slog.String("cK", "cV")))},
+		},
+		"calling PushGroup with a path merges each level into the group attrs": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithGroup("g1").WithAttrs([]slog.Attr{slog.Group("g2", slog.String("aK", "aV"))}).WithGroup("g3").History(),
+			path:             []string{"g1", "g2"},
+			pushAttrs:        []slog.Attr{slog.String("bK", "bV")},
+			want:             []slog.Attr{slog.Group("g1", slog.Group("g2", slog.String("aK", "aV"), slog.String("bK", "bV")))},
+		},
+		"calling PushGroup with a path adds the levels that do not exist as nested groups": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithGroup("g1").WithAttrs([]slog.Attr{slog.String("aK", "aV")}).History(),
+			path:             []string{"g1", "g2", "g3"},
+			pushAttrs:        []slog.Attr{slog.String("bK", "bV")},
+			want:             []slog.Attr{slog.Group("g1", slog.String("aK", "aV"), slog.Group("g2", slog.Group("g3", slog.String("bK", "bV"))))},
+		},
+		"calling PushGroup does not modify the AttrGroupTree": {
+			attrGroupHistory: (func() *internal.AttrGroupHistory {
+				agt := internal.NewAttrGroupTree().WithAttrs([]slog.Attr{slog.Group("g1", slog.String("aK", "aV"))})
+				agt.History().PushGroup([]string{"g1"}, []slog.Attr{slog.String("k", "v")})
+
+				return agt.History()
+			})(),
+			path:      []string{"g2"},
+			pushAttrs: []slog.Attr{slog.String("k2", "v2")},
+			want:      []slog.Attr{slog.Group("g1", slog.String("aK", "aV")), slog.Group("g2", slog.String("k2", "v2"))},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			testCase.attrGroupHistory.PushGroup(testCase.path, testCase.pushAttrs)
+
+			got := testCase.attrGroupHistory.DeduplicatedAttrs()
+
+			if !cmp.Equal(testCase.want, got) {
+				t.Errorf("calling attrGroupHistory.PushGroup(), got: %v, want: %+v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestAttrGroupHistoryMergedAttrs(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		attrGroupHistory *internal.AttrGroupHistory
+		want             []slog.Attr
+	}{
+		"an empty AttrGroupTree has no attrs": {
+			attrGroupHistory: internal.NewAttrGroupTree().History(),
+			want:             []slog.Attr{},
+		},
+		"attrs without groups are deduplicated": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithAttrs([]slog.Attr{slog.String("aK", "aV"), slog.String("aK", "bV")}).History(),
+			want:             []slog.Attr{slog.String("aK", "aV"), slog.String("aK#01", "bV")},
+		},
+		"a group attr with the same name as a descendant group is merged": {
+			attrGroupHistory: internal.NewAttrGroupTree().
+				WithAttrs([]slog.Attr{slog.Group("http", slog.String("method", "GET"))}).
+				WithGroup("http").
+				WithAttrs([]slog.Attr{slog.Int("status", 200)}).
+				History(),
+			want: []slog.Attr{slog.Group("http", slog.String("method", "GET"), slog.Int("status", 200))},
+		},
+		"group attrs with the same name at the same level are merged with duplicate keys deduplicated": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithAttrs([]slog.Attr{
+				slog.Group("g1", slog.String("aK", "aV"), slog.Group("g2", slog.String("bK", "bV"))),
+				slog.String("cK", "cV"),
+				slog.Group("g1", slog.String("aK", "a2V"), slog.Group("g2", slog.String("dK", "dV"))),
+			}).History(),
+			want: []slog.Attr{
+				slog.Group("g1", slog.String("aK", "aV"), slog.Group("g2", slog.String("bK", "bV"), slog.String("dK", "dV")), slog.String("aK#01", "a2V")),
+				slog.String("cK", "cV"),
+			},
+		},
+		"inlined groups are merged into the enclosing group": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithAttrs([]slog.Attr{
+				slog.Group("g1", slog.String("aK", "aV")),
+				slog.Group("", slog.Group("g1", slog.String("bK", "bV"))),
+			}).History(),
+			want: []slog.Attr{slog.Group("g1", slog.String("aK", "aV"), slog.String("bK", "bV"))},
+		},
+		"a group and an attr with the same name are not merged": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithAttrs([]slog.Attr{slog.String("g1", "v")}).WithGroup("g1").WithAttrs([]slog.Attr{slog.String("aK", "aV")}).History(),
+			want:             []slog.Attr{slog.String("g1", "v"), slog.Group("g1#01", slog.String("aK", "aV"))},
+		},
+		"empty descendant groups are omitted": {
+			attrGroupHistory: internal.NewAttrGroupTree().WithAttrs([]slog.Attr{slog.String("aK", "aV")}).WithGroup("g1").History(),
+			want:             []slog.Attr{slog.String("aK", "aV")},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			got := testCase.attrGroupHistory.MergedAttrs()
+
+			if !cmp.Equal(testCase.want, got, cmpopts.EquateEmpty()) {
+				t.Errorf("calling attrGroupHistory.MergedAttrs(), got: %v, want: %+v", got, testCase.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,9 @@ import (
 	"log/slog"
 	"os"
 	"time"
+
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
 )
 
 type (
@@ -12,10 +15,14 @@ type (
 	Option func(*options)
 
 	options struct {
-		level     slog.Leveler
-		addSource bool
-		now       func() time.Time
-		writer    io.Writer
+		level           slog.Leveler
+		addSource       bool
+		now             func() time.Time
+		writer          io.Writer
+		replaceAttr     func(groups []string, attr slog.Attr) slog.Attr
+		structuredErrs  bool
+		inMemoryOptions []slogmem.Option
+		handlerOptions  []slogctx.HandlerOption
 	}
 )
 
@@ -55,12 +62,74 @@ func WithWriter(writer io.Writer) Option {
 	}
 }
 
+// WithReplaceAttr sets [slog.HandlerOptions.ReplaceAttr] which is called to
+// rewrite each attribute before it is logged. The default is nil.
+func WithReplaceAttr(replaceAttr func(groups []string, attr slog.Attr) slog.Attr) Option {
+	return func(o *options) {
+		o.replaceAttr = replaceAttr
+	}
+}
+
+// WithStructuredErrors sets whether attrs with an error value, such as those
+// created with slog.Any("err", err), are rendered as a structured group with the
+// message, type, stack and wrapped errors of the error, see [ErrorValue]. This is
+// applied before the function set by [WithReplaceAttr]. The default is false.
+func WithStructuredErrors(structuredErrs bool) Option {
+	return func(o *options) {
+		o.structuredErrs = structuredErrs
+	}
+}
+
+// WithInMemoryOptions sets the [slogmem.Option] values used to configure the
+// [slogmem.Handler] created by [NewInMemoryLogger], for example, to bound the
+// number of records that are kept with [slogmem.WithCapacity]. This option has
+// no effect on other loggers.
+func WithInMemoryOptions(inMemoryOptions ...slogmem.Option) Option {
+	return func(o *options) {
+		o.inMemoryOptions = append(o.inMemoryOptions, inMemoryOptions...)
+	}
+}
+
+// WithHandlerOptions sets the [slogctx.HandlerOption] values used to configure
+// the [slogctx.Handler] created by each logger, for example, to merge groups
+// with the same name with [slogctx.WithMergedGroups].
+func WithHandlerOptions(handlerOptions ...slogctx.HandlerOption) Option {
+	return func(o *options) {
+		o.handlerOptions = append(o.handlerOptions, handlerOptions...)
+	}
+}
+
+// replaceAttrFunc combines the [TimeFactoryFunc], structured errors and the
+// ReplaceAttr function into a single function that can be used by a
+// [slog.Handler].
+func (o options) replaceAttrFunc() func(groups []string, attr slog.Attr) slog.Attr {
+	return func(groups []string, attr slog.Attr) slog.Attr {
+		if o.now != nil && attr.Key == slog.TimeKey {
+			attr.Value = slog.TimeValue(o.now())
+		}
+
+		if o.structuredErrs {
+			attr = ReplaceErrorAttr(groups, attr)
+		}
+
+		if o.replaceAttr != nil {
+			return o.replaceAttr(groups, attr)
+		}
+
+		return attr
+	}
+}
+
 func mapOptionsToDefaults(opts []Option) options {
 	mappedDefaultOpts := options{
-		level:     slog.LevelInfo,
-		addSource: true,
-		now:       nil,
-		writer:    os.Stderr,
+		level:           slog.LevelInfo,
+		addSource:       true,
+		now:             nil,
+		writer:          os.Stderr,
+		replaceAttr:     nil,
+		structuredErrs:  false,
+		inMemoryOptions: nil,
+		handlerOptions:  nil,
 	}
 
 	for _, opt := range opts {
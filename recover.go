@@ -0,0 +1,178 @@
+package slogutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/nickbryan/slogutil/slogctx"
+)
+
+// PanicKey is the key of the group attr added by [RecoverAndLog] that holds
+// the details of the recovered panic.
+const PanicKey = "panic"
+
+type (
+	// RecoverOption is an optional configuration value used to configure
+	// [RecoverAndLog] and [RecoverMiddleware].
+	RecoverOption func(*recoverOptions)
+
+	recoverOptions struct {
+		message string
+		level   slog.Level
+		repanic bool
+	}
+)
+
+// WithRecoverMessage sets the message of the log written for a recovered
+// panic. The default is "recovered from panic".
+func WithRecoverMessage(message string) RecoverOption {
+	return func(o *recoverOptions) {
+		o.message = message
+	}
+}
+
+// WithRecoverLevel sets the level of the log written for a recovered panic.
+// The default is [slog.LevelError].
+func WithRecoverLevel(level slog.Level) RecoverOption {
+	return func(o *recoverOptions) {
+		o.level = level
+	}
+}
+
+// WithRepanic sets whether the recovered panic is raised again once it has
+// been logged. The default is false.
+func WithRepanic(repanic bool) RecoverOption {
+	return func(o *recoverOptions) {
+		o.repanic = repanic
+	}
+}
+
+// RecoverAndLog recovers from a panic and logs it with the given logger,
+// including the attributes added to ctx via the slogctx package. It must be
+// called directly with defer:
+//
+//	defer slogutil.RecoverAndLog(ctx, logger)
+//
+// When logger is nil, the logger returned by [slogctx.Logger] is used. The log
+// has a [PanicKey] group containing the panic value, its type, the error when
+// the value is an error (see [ErrorValue]) and the stack of the goroutine at
+// the point that it panicked with the runtime frames removed.
+func RecoverAndLog(ctx context.Context, logger *slog.Logger, options ...RecoverOption) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	opts := mapRecoverOptionsToDefaults(options)
+
+	logPanic(ctx, logger, recovered, opts)
+
+	if opts.repanic {
+		panic(recovered)
+	}
+}
+
+// RecoverMiddleware recovers from any panic in next, logs it in the same way
+// as [RecoverAndLog] using the request's context and responds with a 500
+// Internal Server Error. When [WithRepanic] is set, the panic is raised again
+// once it has been logged instead of responding, leaving it to the
+// [http.Server]. A panic with [http.ErrAbortHandler] is never logged and is
+// always raised again so that the response is aborted.
+func RecoverMiddleware(logger *slog.Logger, next http.Handler, options ...RecoverOption) http.Handler {
+	opts := mapRecoverOptionsToDefaults(options)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)
+			}
+
+			logPanic(r.Context(), logger, recovered, opts)
+
+			if opts.repanic {
+				panic(recovered)
+			}
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func mapRecoverOptionsToDefaults(opts []RecoverOption) recoverOptions {
+	mappedDefaultOpts := recoverOptions{
+		message: "recovered from panic",
+		level:   slog.LevelError,
+		repanic: false,
+	}
+
+	for _, opt := range opts {
+		opt(&mappedDefaultOpts)
+	}
+
+	return mappedDefaultOpts
+}
+
+// logPanic writes the log for the recovered panic value.
+func logPanic(ctx context.Context, logger *slog.Logger, recovered any, opts recoverOptions) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if logger == nil {
+		logger = slogctx.Logger(ctx)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("value", fmt.Sprint(recovered)),
+		slog.String("type", fmt.Sprintf("%T", recovered)),
+	}
+
+	if err, ok := recovered.(error); ok {
+		attrs = append(attrs, slog.Attr{Key: "error", Value: ErrorValue(err)})
+	}
+
+	attrs = append(attrs, slog.Any("stack", panicStack()))
+
+	logger.LogAttrs(ctx, opts.level, opts.message, slog.Attr{Key: PanicKey, Value: slog.GroupValue(attrs...)}) //nolint:sloglint // The message is fixed by WithRecoverMessage when the options are mapped.
+}
+
+// panicStack returns the stack of the panicking goroutine as a list of
+// "function file:line" frames, starting at the frame that panicked. The frames
+// of the runtime and of the recovery itself are removed.
+func panicStack() []string {
+	callers := make([]uintptr, maxStackDepth)
+	frames := runtime.CallersFrames(callers[:runtime.Callers(0, callers)])
+	stack := make([]string, 0, maxStackDepth)
+	panicked := false
+
+	for {
+		frame, more := frames.Next()
+
+		// Frames up to and including the runtime's panic handling belong to the
+		// recovery rather than the code that panicked.
+		if frame.Function == "runtime.gopanic" {
+			panicked = true
+		} else if panicked && !strings.HasPrefix(frame.Function, "runtime.") {
+			stack = append(stack, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return stack
+}
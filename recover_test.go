@@ -0,0 +1,202 @@
+package slogutil_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nickbryan/slogutil"
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+var errPanic = errors.New("something went wrong")
+
+func panicWith(value any) {
+	panic(value)
+}
+
+func TestRecoverAndLog(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		value   any
+		options []slogutil.RecoverOption
+		want    slogmem.RecordQuery
+	}{
+		"logs the panic value with the context attrs": {
+			value:   "boom",
+			options: nil,
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelError,
+				Message: "recovered from panic",
+				Attrs: map[string]slog.Value{
+					"request_id":  slog.StringValue("abc"),
+					"panic.value": slog.StringValue("boom"),
+					"panic.type":  slog.StringValue("string"),
+				},
+			},
+		},
+		"logs an error panic value as a structured error": {
+			value:   errPanic,
+			options: []slogutil.RecoverOption{slogutil.WithRecoverMessage("worker crashed"), slogutil.WithRecoverLevel(slog.LevelWarn)},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelWarn,
+				Message: "worker crashed",
+				Attrs: map[string]slog.Value{
+					"request_id":       slog.StringValue("abc"),
+					"panic.value":      slog.StringValue("something went wrong"),
+					"panic.type":       slog.StringValue("*errors.errorString"),
+					"panic.error.msg":  slog.StringValue("something went wrong"),
+					"panic.error.type": slog.StringValue("*errors.errorString"),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo)
+			ctx := slogctx.WithAttrs(context.Background(), slog.String("request_id", "abc"))
+
+			func() {
+				defer slogutil.RecoverAndLog(ctx, logger, tc.options...)
+
+				panicWith(tc.value)
+			}()
+
+			if ok, diff := logs.Contains(tc.want); !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", tc.want, diff)
+			}
+
+			for record := range logs.All() {
+				stack, _ := record.Attr("panic.stack")
+
+				frames, _ := stack.Any().([]string)
+				if len(frames) == 0 || !strings.HasPrefix(frames[0], "github.com/nickbryan/slogutil_test.panicWith ") {
+					t.Errorf("expected the first frame to be the function that panicked, got: %v", frames)
+				}
+
+				for _, frame := range frames {
+					if strings.HasPrefix(frame, "runtime.") {
+						t.Errorf("expected runtime frames to be removed, got: %v", frames)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRecoverAndLogUsesTheContextLoggerWhenLoggerIsNil(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo)
+	ctx := slogctx.WithLogger(context.Background(), logger)
+
+	func() {
+		defer slogutil.RecoverAndLog(ctx, nil)
+
+		panicWith("boom")
+	}()
+
+	query := slogmem.RecordQuery{
+		Level:   slog.LevelError,
+		Message: "recovered from panic",
+		Attrs:   map[string]slog.Value{"panic.value": slog.StringValue("boom")},
+	}
+
+	if ok, diff := logs.Contains(query); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+	}
+}
+
+func TestRecoverAndLogRepanics(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo)
+
+	defer func() {
+		if recovered := recover(); recovered != "boom" {
+			t.Errorf("expected the panic to be raised again, got: %v", recovered)
+		}
+
+		if got := logs.Len(); got != 1 {
+			t.Errorf("logs.Len() want: 1, got: %d", got)
+		}
+	}()
+
+	defer slogutil.RecoverAndLog(context.Background(), logger, slogutil.WithRepanic(true))
+
+	panicWith("boom")
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		value      any
+		wantStatus int
+		wantLogs   int
+	}{
+		"logs the panic and responds with an internal server error": {
+			value:      "boom",
+			wantStatus: http.StatusInternalServerError,
+			wantLogs:   1,
+		},
+		"does not log a panic that aborts the handler": {
+			value:      http.ErrAbortHandler,
+			wantStatus: 0,
+			wantLogs:   0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo)
+
+			handler := slogutil.RecoverMiddleware(logger, http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+				panicWith(tc.value)
+			}))
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request = request.WithContext(slogctx.WithAttrs(request.Context(), slog.String("path", "/")))
+
+			func() {
+				defer func() {
+					if recovered := recover(); recovered != nil && tc.wantStatus != 0 {
+						t.Errorf("expected the panic to be recovered, got: %v", recovered)
+					}
+				}()
+
+				handler.ServeHTTP(recorder, request)
+			}()
+
+			if tc.wantStatus != 0 && recorder.Code != tc.wantStatus {
+				t.Errorf("recorder.Code want: %d, got: %d", tc.wantStatus, recorder.Code)
+			}
+
+			if got := logs.Len(); got != tc.wantLogs {
+				t.Errorf("logs.Len() want: %d, got: %d", tc.wantLogs, got)
+			}
+
+			query := slogmem.RecordQuery{
+				Level:   slog.LevelError,
+				Message: "recovered from panic",
+				Attrs:   map[string]slog.Value{"path": slog.StringValue("/"), "panic.value": slog.StringValue("boom")},
+			}
+
+			if ok, diff := logs.Contains(query); tc.wantLogs > 0 && !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// BaggageHeader is the name of the W3C baggage HTTP header.
+const BaggageHeader = "baggage"
+
+type (
+	ctxKeyBaggage struct{}
+
+	// BaggageOption is an optional configuration value used to configure a
+	// [BaggageExtractor].
+	BaggageOption func(*baggageOptions)
+
+	baggageOptions struct {
+		members    []string
+		keyPrefix  string
+		maxMembers int
+		maxBytes   int
+		sources    []func(ctx context.Context) string
+	}
+
+	// parsedBaggage is W3C encoded baggage that has been parsed into its
+	// members, along with the size of the encoded baggage in bytes.
+	parsedBaggage struct {
+		members []baggageMember
+		size    int
+	}
+
+	// baggageMember is a single well-formed member of W3C encoded baggage. End
+	// is the offset of the end of the member within the encoded baggage so that
+	// the byte limit of a [BaggageExtractor] can be applied.
+	baggageMember struct {
+		key, value string
+		end        int
+	}
+)
+
+// BaggageExtractor is an [Extractor] that extracts the members of W3C baggage
+// as [slog.Attr] values. It is created with [NewBaggageExtractor].
+type BaggageExtractor struct {
+	opts baggageOptions
+}
+
+// Ensure that [BaggageExtractor] implements [Extractor].
+var _ Extractor = BaggageExtractor{} //nolint:exhaustruct // Compile time implementation check.
+
+// WithBaggageMembers restricts the extracted baggage members to those with the
+// given keys. The default is to extract all members.
+func WithBaggageMembers(keys ...string) BaggageOption {
+	return func(o *baggageOptions) {
+		o.members = append(o.members, keys...)
+	}
+}
+
+// WithBaggageKeyPrefix sets the prefix added to the key of each baggage member
+// to create the attr key. The default is "baggage_".
+func WithBaggageKeyPrefix(prefix string) BaggageOption {
+	return func(o *baggageOptions) {
+		o.keyPrefix = prefix
+	}
+}
+
+// WithBaggageMaxMembers sets the maximum number of baggage members that are
+// extracted. Any members over the limit are dropped. The default is 64.
+func WithBaggageMaxMembers(maxMembers int) BaggageOption {
+	return func(o *baggageOptions) {
+		o.maxMembers = maxMembers
+	}
+}
+
+// WithBaggageMaxBytes sets the maximum number of bytes of baggage that will be
+// read. Any members after the limit are dropped. The default is 8192, the
+// limit set by the W3C baggage specification.
+func WithBaggageMaxBytes(maxBytes int) BaggageOption {
+	return func(o *baggageOptions) {
+		o.maxBytes = maxBytes
+	}
+}
+
+// WithBaggageSource adds a function that returns W3C encoded baggage for a
+// [context.Context]. This allows baggage to be read from a tracing library
+// without slogctx depending on it, for example with OpenTelemetry:
+//
+//	slogctx.WithBaggageSource(func(ctx context.Context) string {
+//		return baggage.FromContext(ctx).String()
+//	})
+//
+// Sources are read in the order that they were added, followed by any baggage
+// added to the [context.Context] via [WithBaggage] or [BaggageMiddleware].
+func WithBaggageSource(source func(ctx context.Context) string) BaggageOption {
+	return func(o *baggageOptions) {
+		o.sources = append(o.sources, source)
+	}
+}
+
+// NewBaggageExtractor creates a [BaggageExtractor] that extracts the members of
+// the W3C baggage carried by a [context.Context] as string attrs. When a member
+// appears more than once, the first value is used. Members whose attr key would
+// be one of the keys used by [slog.Handler] for the time, level, message or
+// source of the record are dropped.
+//
+// Baggage is set by the caller, so when it is read from inbound requests
+// restricting the members with [WithBaggageMembers] is strongly recommended in
+// order to stop clients from adding arbitrary attrs to the logs.
+func NewBaggageExtractor(options ...BaggageOption) BaggageExtractor {
+	const defaultMaxMembers, defaultMaxBytes = 64, 8192
+
+	opts := baggageOptions{
+		members:    nil,
+		keyPrefix:  "baggage_",
+		maxMembers: defaultMaxMembers,
+		maxBytes:   defaultMaxBytes,
+		sources:    nil,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return BaggageExtractor{opts: opts}
+}
+
+// WithBaggage returns a copy of ctx that carries the given W3C encoded baggage
+// so that it can be extracted by a [BaggageExtractor]. The baggage is parsed
+// once here rather than each time a record is logged.
+func WithBaggage(ctx context.Context, baggage string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, ctxKeyBaggage{}, parseBaggage(baggage))
+}
+
+// BaggageMiddleware adds the W3C baggage headers of each request to the
+// request's [context.Context] via [WithBaggage].
+func BaggageMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if baggage := r.Header.Values(BaggageHeader); len(baggage) > 0 {
+			r = r.WithContext(WithBaggage(r.Context(), strings.Join(baggage, ",")))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Extract implements the [Extractor] interface.
+func (e BaggageExtractor) Extract(ctx context.Context) []slog.Attr {
+	parsed := make([]parsedBaggage, 0, len(e.opts.sources)+1)
+
+	for _, source := range e.opts.sources {
+		if baggage := source(ctx); baggage != "" {
+			parsed = append(parsed, parseBaggage(baggage))
+		}
+	}
+
+	if baggage, ok := ctx.Value(ctxKeyBaggage{}).(parsedBaggage); ok && baggage.size > 0 {
+		parsed = append(parsed, baggage)
+	}
+
+	if len(parsed) == 0 {
+		return nil
+	}
+
+	return e.attrs(parsed)
+}
+
+// attrs converts the selected members of the parsed baggage into attrs as if
+// the baggage had been joined with commas, dropping the members after the
+// member or byte limit.
+func (e BaggageExtractor) attrs(parsed []parsedBaggage) []slog.Attr {
+	var (
+		attrs  []slog.Attr
+		seen   = make(map[string]bool)
+		offset int
+	)
+
+	for _, baggage := range parsed {
+		for _, member := range baggage.members {
+			if len(attrs) >= e.opts.maxMembers || offset+member.end > e.opts.maxBytes {
+				return attrs
+			}
+
+			key := e.opts.keyPrefix + member.key
+			if seen[member.key] || isBuiltinKey(key) || (len(e.opts.members) > 0 && !slices.Contains(e.opts.members, member.key)) {
+				continue
+			}
+
+			seen[member.key] = true
+			attrs = append(attrs, slog.String(key, member.value))
+		}
+
+		offset += baggage.size + 1 // The comma joining the baggage.
+	}
+
+	return attrs
+}
+
+// parseBaggage splits W3C encoded baggage into its members, skipping any that
+// are malformed. Member properties are ignored.
+func parseBaggage(baggage string) parsedBaggage {
+	parsed := parsedBaggage{members: nil, size: len(baggage)}
+	end := -1 // No comma precedes the first member.
+
+	for _, member := range strings.Split(baggage, ",") {
+		end += len(member) + 1
+
+		member, _, _ = strings.Cut(member, ";")
+
+		key, value, ok := strings.Cut(member, "=")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			continue
+		}
+
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+
+		parsed.members = append(parsed.members, baggageMember{key: key, value: value, end: end})
+	}
+
+	return parsed
+}
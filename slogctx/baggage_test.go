@@ -0,0 +1,153 @@
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestBaggageExtractor(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		baggage string
+		options []slogctx.BaggageOption
+		atRoot  bool
+		want    slogmem.RecordQuery
+	}{
+		"extracts all members with the default prefix": {
+			baggage: "tenant=acme, region=eu%20west;ttl=30",
+			options: nil,
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"g1.baggage_tenant": slog.StringValue("acme"),
+					"g1.baggage_region": slog.StringValue("eu west"),
+				},
+			},
+		},
+		"extracts selected members with a custom prefix at the root": {
+			baggage: "tenant=acme,region=eu,experiment=b",
+			options: []slogctx.BaggageOption{
+				slogctx.WithBaggageMembers("tenant", "experiment"),
+				slogctx.WithBaggageKeyPrefix("bg_"),
+			},
+			atRoot: true,
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"bg_tenant":     slog.StringValue("acme"),
+					"bg_experiment": slog.StringValue("b"),
+				},
+			},
+		},
+		"extracts members from a source before the context": {
+			baggage: "tenant=header,region=eu",
+			options: []slogctx.BaggageOption{
+				slogctx.WithBaggageSource(func(_ context.Context) string { return "tenant=source" }),
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"g1.baggage_tenant": slog.StringValue("source"),
+					"g1.baggage_region": slog.StringValue("eu"),
+				},
+			},
+		},
+		"skips malformed members and members over the size limit": {
+			baggage: "bad,=empty,escape=%zz,tenant=acme," + strings.Repeat("x", 100) + "=1",
+			options: []slogctx.BaggageOption{slogctx.WithBaggageMaxBytes(64)},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"g1.baggage_tenant": slog.StringValue("acme")},
+			},
+		},
+		"drops members with the keys used for the record's built-in attrs": {
+			baggage: "msg=spoofed,level=DEBUG,tenant=acme",
+			options: []slogctx.BaggageOption{slogctx.WithBaggageKeyPrefix("")},
+			atRoot:  true,
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"tenant": slog.StringValue("acme")},
+			},
+		},
+		"drops members over the member limit": {
+			baggage: "tenant=acme,region=eu,experiment=b",
+			options: []slogctx.BaggageOption{slogctx.WithBaggageMaxMembers(2)},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"g1.baggage_tenant": slog.StringValue("acme"),
+					"g1.baggage_region": slog.StringValue("eu"),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := slogmem.NewHandler(slog.LevelDebug)
+			ctxHandler := slogctx.NewHandler(handler)
+
+			var extractor slogctx.Extractor = slogctx.NewBaggageExtractor(tc.options...)
+			if tc.atRoot {
+				extractor = slogctx.ExtractAtRoot(extractor)
+			}
+
+			ctxHandler.AddExtractors(extractor)
+
+			ctx := slogctx.WithBaggage(context.Background(), tc.baggage)
+			slog.New(ctxHandler).WithGroup("g1").InfoContext(ctx, "Test message")
+
+			if ok, diff := handler.Records().ContainsExact(tc.want); !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestBaggageMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	ctxHandler := slogctx.NewHandler(handler)
+	ctxHandler.AddExtractors(slogctx.NewBaggageExtractor())
+	logger := slog.New(ctxHandler)
+
+	server := slogctx.BaggageMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "Test message")
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Add(slogctx.BaggageHeader, "tenant=acme")
+	request.Header.Add(slogctx.BaggageHeader, "region=eu")
+
+	server.ServeHTTP(httptest.NewRecorder(), request)
+
+	want := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "Test message",
+		Attrs: map[string]slog.Value{
+			"baggage_tenant": slog.StringValue("acme"),
+			"baggage_region": slog.StringValue("eu"),
+		},
+	}
+
+	if ok, diff := handler.Records().ContainsExact(want); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", want, diff)
+	}
+}
@@ -0,0 +1,252 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+type (
+	ctxKeyLogBuffer struct{}
+
+	// BufferOption is an optional configuration value used to configure a
+	// [BufferingHandler].
+	BufferOption func(*bufferOptions)
+
+	bufferOptions struct {
+		minLevel    slog.Leveler
+		bufferLevel slog.Leveler
+		flushLevel  slog.Leveler
+		limit       int
+	}
+
+	// logBuffer holds the records for a single [context.Context] until it is
+	// marked as failed, at which point they are written and all subsequent
+	// records pass straight through.
+	logBuffer struct {
+		mu      sync.Mutex
+		records []bufferedRecord
+		failed  bool
+	}
+
+	// bufferedRecord is a record along with the handler and context that it
+	// should be written with when the buffer is flushed.
+	bufferedRecord struct {
+		ctx     context.Context //nolint:containedctx // The context is required to write the record when flushed.
+		handler slog.Handler
+		record  slog.Record
+	}
+)
+
+// BufferingHandler holds low level records in memory for each
+// [context.Context] created with [WithLogBuffer] and only writes them to the
+// wrapped [slog.Handler] if the context is later marked as failed, either by
+// a record being logged at or above the flush level or by calling
+// [MarkFailed]. Buffered records are otherwise discarded along with the
+// context. Records logged with a context that has no buffer are passed
+// straight to the wrapped handler.
+//
+// The BufferingHandler is intended to be wrapped by a [Handler] so that
+// context attributes are resolved before the records are buffered:
+//
+//	slogctx.NewHandler(slogctx.NewBufferingHandler(slog.NewJSONHandler(os.Stderr, nil)))
+type BufferingHandler struct {
+	slog.Handler
+
+	opts bufferOptions
+}
+
+// Ensure that our [BufferingHandler] implements the [slog.Handler] interface.
+var _ slog.Handler = &BufferingHandler{} //nolint:exhaustruct // Compile time implementation check.
+
+// WithMinBufferLevel sets the level below which records are not buffered.
+// Records below this level are only written when the wrapped handler is
+// enabled for them. The default is [slog.LevelDebug].
+func WithMinBufferLevel(level slog.Leveler) BufferOption {
+	return func(o *bufferOptions) {
+		o.minLevel = level
+	}
+}
+
+// WithBufferLevel sets the level below which records are buffered. Records at
+// or above this level are written immediately. The default is [slog.LevelInfo].
+func WithBufferLevel(level slog.Leveler) BufferOption {
+	return func(o *bufferOptions) {
+		o.bufferLevel = level
+	}
+}
+
+// WithFlushLevel sets the level at or above which a record will mark the
+// buffer as failed and flush it. The default is [slog.LevelError].
+func WithFlushLevel(level slog.Leveler) BufferOption {
+	return func(o *bufferOptions) {
+		o.flushLevel = level
+	}
+}
+
+// WithBufferLimit sets the maximum number of records that will be buffered for
+// each [context.Context]. Once the limit is reached, the oldest record is
+// dropped to make room for the newest. The default is 100.
+func WithBufferLimit(limit int) BufferOption {
+	return func(o *bufferOptions) {
+		o.limit = limit
+	}
+}
+
+// NewBufferingHandler creates a new BufferingHandler that writes records to
+// the wrapped [slog.Handler] once the [context.Context] they were logged with
+// has been marked as failed.
+func NewBufferingHandler(wrapped slog.Handler, options ...BufferOption) *BufferingHandler {
+	const defaultLimit = 100
+
+	opts := bufferOptions{
+		minLevel:    slog.LevelDebug,
+		bufferLevel: slog.LevelInfo,
+		flushLevel:  slog.LevelError,
+		limit:       defaultLimit,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return &BufferingHandler{Handler: wrapped, opts: opts}
+}
+
+// WithLogBuffer returns a copy of ctx that will have its low level records
+// buffered by a [BufferingHandler]. This would typically be called by
+// middleware at the start of each request.
+func WithLogBuffer(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, ctxKeyLogBuffer{}, &logBuffer{mu: sync.Mutex{}, records: nil, failed: false})
+}
+
+// MarkFailed writes any records that have been buffered for ctx and causes all
+// subsequent records logged with ctx to be passed straight to the wrapped
+// handler. It does nothing when ctx was not created with [WithLogBuffer].
+func MarkFailed(ctx context.Context) error {
+	buffer := logBufferFromContext(ctx)
+	if buffer == nil {
+		return nil
+	}
+
+	return buffer.fail()
+}
+
+// Enabled reports whether the wrapped handler is enabled for the given level.
+// Levels at or above the minimum buffer level are also enabled for a ctx
+// created with [WithLogBuffer] so that they can be buffered.
+func (h *BufferingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if logBufferFromContext(ctx) != nil && level >= h.opts.minLevel.Level() {
+		return true
+	}
+
+	return h.Handler.Enabled(ctx, level)
+}
+
+// WithAttrs returns a new BufferingHandler wrapping the result of calling
+// WithAttrs on the wrapped handler.
+func (h *BufferingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &BufferingHandler{Handler: h.Handler.WithAttrs(attrs), opts: h.opts}
+}
+
+// WithGroup returns a new BufferingHandler wrapping the result of calling
+// WithGroup on the wrapped handler.
+func (h *BufferingHandler) WithGroup(name string) slog.Handler {
+	return &BufferingHandler{Handler: h.Handler.WithGroup(name), opts: h.opts}
+}
+
+// Handle buffers the record when ctx was created with [WithLogBuffer] and the
+// record is between the minimum buffer level and the buffer level. A record at
+// or above the flush level marks the buffer as failed, writing the buffered
+// records before the record itself.
+func (h *BufferingHandler) Handle(ctx context.Context, record slog.Record) error {
+	buffer := logBufferFromContext(ctx)
+	if buffer == nil {
+		return h.handle(ctx, record)
+	}
+
+	if record.Level >= h.opts.flushLevel.Level() {
+		if err := buffer.fail(); err != nil {
+			return err
+		}
+	} else if h.buffers(record.Level) && buffer.add(ctx, h.Handler, record, h.opts.limit) {
+		return nil
+	}
+
+	// Enabled reports true for the buffered levels so we must respect the level
+	// of the wrapped handler for records that are not buffered.
+	if !h.Handler.Enabled(ctx, record.Level) {
+		return nil
+	}
+
+	return h.handle(ctx, record)
+}
+
+// buffers reports whether records of the given level are held in the buffer.
+func (h *BufferingHandler) buffers(level slog.Level) bool {
+	return level >= h.opts.minLevel.Level() && level < h.opts.bufferLevel.Level()
+}
+
+// handle passes the record to the wrapped handler.
+func (h *BufferingHandler) handle(ctx context.Context, record slog.Record) error {
+	if err := h.Handler.Handle(ctx, record); err != nil {
+		return fmt.Errorf("passing record to inner handler: %w", err)
+	}
+
+	return nil
+}
+
+// add buffers the record, returning false if the buffer has already failed and
+// the record should be written immediately instead.
+func (b *logBuffer) add(ctx context.Context, handler slog.Handler, record slog.Record, limit int) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failed {
+		return false
+	}
+
+	if limit > 0 && len(b.records) >= limit {
+		b.records = b.records[len(b.records)-limit+1:]
+	}
+
+	b.records = append(b.records, bufferedRecord{ctx: ctx, handler: handler, record: record.Clone()})
+
+	return true
+}
+
+// fail marks the buffer as failed and writes any buffered records in the order
+// that they were logged.
+func (b *logBuffer) fail() error {
+	b.mu.Lock()
+	records := b.records
+	b.records, b.failed = nil, true
+	b.mu.Unlock()
+
+	var errs []error
+
+	for _, buffered := range records {
+		if err := buffered.handler.Handle(buffered.ctx, buffered.record); err != nil {
+			errs = append(errs, fmt.Errorf("flushing buffered record to inner handler: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// logBufferFromContext returns the buffer stored in ctx or nil if there is not one.
+func logBufferFromContext(ctx context.Context) *logBuffer {
+	if ctx == nil {
+		return nil
+	}
+
+	buffer, _ := ctx.Value(ctxKeyLogBuffer{}).(*logBuffer)
+
+	return buffer
+}
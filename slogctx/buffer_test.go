@@ -0,0 +1,237 @@
+package slogctx_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestBufferingHandler(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options  []slogctx.BufferOption
+		log      func(ctx context.Context, logger *slog.Logger)
+		wantMsgs []string
+	}{
+		"discards buffered records when the request does not fail": {
+			options: nil,
+			log: func(ctx context.Context, logger *slog.Logger) {
+				ctx = slogctx.WithLogBuffer(ctx)
+				logger.DebugContext(ctx, "debug")
+				logger.InfoContext(ctx, "info")
+			},
+			wantMsgs: []string{"info"},
+		},
+		"writes buffered records before the record that fails the request": {
+			options: nil,
+			log: func(ctx context.Context, logger *slog.Logger) {
+				ctx = slogctx.WithLogBuffer(ctx)
+				logger.DebugContext(ctx, "first debug")
+				logger.InfoContext(ctx, "info")
+				logger.DebugContext(ctx, "second debug")
+				logger.ErrorContext(ctx, "error")
+				logger.DebugContext(ctx, "debug after failure")
+				logger.InfoContext(ctx, "info after failure")
+			},
+			wantMsgs: []string{"info", "first debug", "second debug", "error", "info after failure"},
+		},
+		"writes buffered records when the request is marked as failed": {
+			options: nil,
+			log: func(ctx context.Context, logger *slog.Logger) {
+				ctx = slogctx.WithLogBuffer(ctx)
+				logger.DebugContext(ctx, "debug")
+
+				if err := slogctx.MarkFailed(ctx); err != nil {
+					panic(err)
+				}
+			},
+			wantMsgs: []string{"debug"},
+		},
+		"keeps only the most recent records when the buffer limit is reached": {
+			options: []slogctx.BufferOption{slogctx.WithBufferLimit(2)},
+			log: func(ctx context.Context, logger *slog.Logger) {
+				ctx = slogctx.WithLogBuffer(ctx)
+				logger.DebugContext(ctx, "first debug")
+				logger.DebugContext(ctx, "second debug")
+				logger.DebugContext(ctx, "third debug")
+				logger.ErrorContext(ctx, "error")
+			},
+			wantMsgs: []string{"second debug", "third debug", "error"},
+		},
+		"respects custom buffer and flush levels": {
+			options: []slogctx.BufferOption{slogctx.WithBufferLevel(slog.LevelWarn), slogctx.WithFlushLevel(slog.LevelWarn)},
+			log: func(ctx context.Context, logger *slog.Logger) {
+				ctx = slogctx.WithLogBuffer(ctx)
+				logger.InfoContext(ctx, "info")
+				logger.WarnContext(ctx, "warn")
+			},
+			wantMsgs: []string{"info", "warn"},
+		},
+		"does not buffer records below the minimum buffer level": {
+			options: []slogctx.BufferOption{slogctx.WithMinBufferLevel(slog.LevelDebug)},
+			log: func(ctx context.Context, logger *slog.Logger) {
+				ctx = slogctx.WithLogBuffer(ctx)
+				logger.Log(ctx, slog.LevelDebug-4, "trace")
+				logger.DebugContext(ctx, "debug")
+				logger.ErrorContext(ctx, "error")
+			},
+			wantMsgs: []string{"debug", "error"},
+		},
+		"keeps requests isolated from each other": {
+			options: nil,
+			log: func(ctx context.Context, logger *slog.Logger) {
+				failingCtx := slogctx.WithLogBuffer(ctx)
+				succeedingCtx := slogctx.WithLogBuffer(ctx)
+				logger.DebugContext(failingCtx, "failing debug")
+				logger.DebugContext(succeedingCtx, "succeeding debug")
+				logger.ErrorContext(failingCtx, "error")
+			},
+			wantMsgs: []string{"failing debug", "error"},
+		},
+		"passes records straight through for a context without a buffer": {
+			options: nil,
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.DebugContext(ctx, "debug")
+				logger.InfoContext(ctx, "info")
+			},
+			wantMsgs: []string{"info"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := slogmem.NewHandler(slog.LevelInfo)
+			logger := slog.New(slogctx.NewHandler(slogctx.NewBufferingHandler(handler, tc.options...)))
+
+			tc.log(context.Background(), logger)
+
+			var gotMsgs []string
+			for record := range handler.Records().All() {
+				gotMsgs = append(gotMsgs, record.Message)
+			}
+
+			if !slices.Equal(tc.wantMsgs, gotMsgs) {
+				t.Errorf("logged messages want: %v, got: %v", tc.wantMsgs, gotMsgs)
+			}
+		})
+	}
+}
+
+func TestBufferingHandlerPreservesContextAttrs(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelInfo)
+	logger := slog.New(slogctx.NewHandler(slogctx.NewBufferingHandler(handler))).WithGroup("g1")
+
+	ctx := slogctx.WithLogBuffer(context.Background())
+	ctx = slogctx.WithRootAttrs(ctx, slog.String("request_id", "abc"))
+	ctx = slogctx.WithAttrs(ctx, slog.String("p1", "v1"))
+
+	logger.DebugContext(ctx, "debug", slog.Int("e1", 123))
+	logger.ErrorContext(ctx, "error")
+
+	query := slogmem.RecordQuery{
+		Level:   slog.LevelDebug,
+		Message: "debug",
+		Attrs: map[string]slog.Value{
+			"request_id": slog.StringValue("abc"),
+			"g1.e1":      slog.IntValue(123),
+			"g1.p1":      slog.StringValue("v1"),
+		},
+	}
+
+	if ok, diff := handler.Records().ContainsExact(query); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+	}
+}
+
+func TestBufferingHandlerReturnsErrorWhenFlushingFails(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slogctx.NewBufferingHandler(erroringHandler{err: errors.New("some internal error")}))
+	ctx := slogctx.WithLogBuffer(context.Background())
+
+	logger.DebugContext(ctx, "debug")
+
+	err := slogctx.MarkFailed(ctx)
+	if err == nil {
+		t.Fatalf("no error returned from slogctx.MarkFailed")
+	}
+
+	expectedMsg := "flushing buffered record to inner handler: some internal error"
+	if err.Error() != expectedMsg {
+		t.Errorf("expected err.Error() == %q got: %q", expectedMsg, err.Error())
+	}
+}
+
+func TestBufferingHandlerPreservesHandlerAttrsAndGroups(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelInfo)
+	logger := slog.New(slogctx.NewBufferingHandler(handler)).With(slog.String("service", "api")).WithGroup("g1")
+
+	ctx := slogctx.WithLogBuffer(context.Background())
+
+	logger.DebugContext(ctx, "debug", slog.Int("e1", 123))
+	logger.ErrorContext(ctx, "error")
+
+	query := slogmem.RecordQuery{
+		Level:   slog.LevelDebug,
+		Message: "debug",
+		Attrs: map[string]slog.Value{
+			"service": slog.StringValue("api"),
+			"g1.e1":   slog.IntValue(123),
+		},
+	}
+
+	if ok, diff := handler.Records().ContainsExact(query); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+	}
+}
+
+func TestBufferingHandlerEnabled(t *testing.T) {
+	t.Parallel()
+
+	handler := slogctx.NewBufferingHandler(slogmem.NewHandler(slog.LevelInfo), slogctx.WithMinBufferLevel(slog.LevelDebug))
+	bufferedCtx := slogctx.WithLogBuffer(context.Background())
+
+	testCases := map[string]struct {
+		ctx   context.Context
+		level slog.Level
+		want  bool
+	}{
+		"enables the buffered levels for a context with a buffer": {
+			ctx:   bufferedCtx,
+			level: slog.LevelDebug,
+			want:  true,
+		},
+		"disables levels below the minimum buffer level": {
+			ctx:   bufferedCtx,
+			level: slog.LevelDebug - 4,
+			want:  false,
+		},
+		"uses the wrapped handler's level for a context without a buffer": {
+			ctx:   context.Background(),
+			level: slog.LevelDebug,
+			want:  false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := handler.Enabled(tc.ctx, tc.level); got != tc.want {
+				t.Errorf("handler.Enabled(%s) want: %t, got: %t", tc.level, tc.want, got)
+			}
+		})
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"log/slog"
 	"slices"
+	"strings"
+	"sync"
 )
 
 type (
-	ctxKeyWithAttrs     struct{}
-	ctxKeyWithRootAttrs struct{}
+	ctxKeyWithAttrs        struct{}
+	ctxKeyWithRootAttrs    struct{}
+	ctxKeyWithGroupedAttrs struct{}
 )
 
 // WithAttrs will add attrs to the [context.Context] so that they can be
@@ -41,7 +44,60 @@ func WithRootAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
 	return addToContext(ctx, ctxKeyWithRootAttrs{}, attrs)
 }
 
-func addToContext[K ctxKeyWithAttrs | ctxKeyWithRootAttrs](ctx context.Context, key K, attrs []slog.Attr) context.Context {
+// WithGroupedAttrs will add attrs to the [context.Context] so that they can be
+// placed within the named group at the root of the log when the log is written
+// with the given [context.Context], regardless of the group that the logger is
+// in. This is helpful when you want attrs to be under a known group, such as
+// "http", in every log. The group may be a dot separated path, such as
+// "http.request", to place the attrs within nested groups.
+//
+// When the logger's first group or a group attr at the root of the log has the
+// same name, the attrs are merged into that group rather than a second group
+// being added. This applies at each level of a dot separated path. Making
+// subsequent calls to this on the same [context.Context] will result in attrs
+// being appended to the group. This is safe to do.
+func WithGroupedAttrs(ctx context.Context, group string, attrs ...slog.Attr) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return addToContext(ctx, ctxKeyWithGroupedAttrs{}, []slog.Attr{{Key: group, Value: slog.GroupValue(attrs...)}})
+}
+
+// groupPath splits the dot separated group passed to [WithGroupedAttrs] into
+// the names of each group from the root.
+func groupPath(group string) []string {
+	if group == "" {
+		return nil
+	}
+
+	return strings.Split(group, ".")
+}
+
+// lazyValue is a [slog.LogValuer] that evaluates its value at most once, the
+// first time that it is resolved.
+type lazyValue struct {
+	value func() slog.Value
+}
+
+// LogValue implements the [slog.LogValuer] interface.
+func (lv lazyValue) LogValue() slog.Value {
+	return lv.value()
+}
+
+// WithLazyAttrs will add an attr with the given key to the [context.Context]
+// in the same way as [WithAttrs], but the value of the attr is only computed by
+// calling value when a log that is enabled is written with the
+// [context.Context]. This is helpful when the value is expensive to compute.
+//
+// The value is computed at most once for the [context.Context] and any
+// [context.Context] derived from it, regardless of how many logs are written.
+// It is safe for the logs to be written concurrently.
+func WithLazyAttrs(ctx context.Context, key string, value func() slog.Value) context.Context {
+	return WithAttrs(ctx, slog.Any(key, lazyValue{value: sync.OnceValue(value)}))
+}
+
+func addToContext[K ctxKeyWithAttrs | ctxKeyWithRootAttrs | ctxKeyWithGroupedAttrs](ctx context.Context, key K, attrs []slog.Attr) context.Context {
 	if existingAttrs, ok := ctx.Value(key).([]slog.Attr); ok {
 		return context.WithValue(ctx, key, append(slices.Clip(existingAttrs), slices.Clip(attrs)...))
 	}
@@ -3,6 +3,8 @@ package slogctx_test
 import (
 	"context"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/nickbryan/slogutil/slogctx"
@@ -238,3 +240,166 @@ func TestWithRootAttrs(t *testing.T) {
 		})
 	}
 }
+
+func TestWithGroupedAttrs(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ctx  context.Context
+		log  func(ctx context.Context, logger *slog.Logger)
+		want slogmem.RecordQuery
+	}{
+		"adding grouped attrs to a log entry adds the group at the root": {
+			ctx: slogctx.WithGroupedAttrs(context.Background(), "http", slog.String("method", "GET")),
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.WithGroup("g1").InfoContext(ctx, "Test message", slog.Int("e1", 123))
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"http.method": slog.StringValue("GET"), "g1.e1": slog.IntValue(123)},
+			},
+		},
+		"adding grouped attrs to a log entry from a logger in the same group merges the groups": {
+			ctx: slogctx.WithGroupedAttrs(context.Background(), "http", slog.String("method", "GET")),
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.WithGroup("http").InfoContext(ctx, "Test message", slog.Int("status", 200))
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"http.status": slog.IntValue(200), "http.method": slog.StringValue("GET")},
+			},
+		},
+		"adding grouped attrs to a log entry with a group attr of the same name merges the groups": {
+			ctx: slogctx.WithGroupedAttrs(context.Background(), "http", slog.String("method", "GET"), slog.Int("status", 500)),
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.InfoContext(ctx, "Test message", slog.Group("http", slog.Int("status", 200)))
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"http.status":    slog.IntValue(200),
+					"http.method":    slog.StringValue("GET"),
+					"http.status#01": slog.IntValue(500),
+				},
+			},
+		},
+		"adding grouped attrs with a path nests the groups and merges each level with the logger's groups": {
+			ctx: slogctx.WithGroupedAttrs(context.Background(), "http.request", slog.String("method", "GET")),
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.WithGroup("http").InfoContext(ctx, "Test message", slog.Group("request", slog.String("path", "/")), slog.Int("status", 200))
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"http.request.path":   slog.StringValue("/"),
+					"http.request.method": slog.StringValue("GET"),
+					"http.status":         slog.IntValue(200),
+				},
+			},
+		},
+		"adding grouped attrs with a path merges into nested logger groups": {
+			ctx: slogctx.WithGroupedAttrs(context.Background(), "http.request", slog.String("method", "GET")),
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.WithGroup("http").WithGroup("request").InfoContext(ctx, "Test message", slog.String("path", "/"))
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"http.request.path": slog.StringValue("/"), "http.request.method": slog.StringValue("GET")},
+			},
+		},
+		"adding grouped attrs with a path adds nested groups at the root": {
+			ctx: slogctx.WithGroupedAttrs(context.Background(), "http.request", slog.String("method", "GET")),
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.WithGroup("g1").InfoContext(ctx, "Test message", slog.Int("e1", 123))
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"http.request.method": slog.StringValue("GET"), "g1.e1": slog.IntValue(123)},
+			},
+		},
+		"adding grouped attrs to a ctx with existing grouped attrs appends to the group": {
+			ctx: slogctx.WithGroupedAttrs(
+				slogctx.WithGroupedAttrs(context.Background(), "http", slog.String("method", "GET")),
+				"http", slog.String("path", "/"),
+			),
+			log: func(ctx context.Context, logger *slog.Logger) {
+				logger.InfoContext(ctx, "Test message")
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"http.method": slog.StringValue("GET"), "http.path": slog.StringValue("/")},
+			},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			handler := slogmem.NewHandler(slog.LevelDebug)
+			logger := slog.New(slogctx.NewHandler(handler))
+
+			testCase.log(testCase.ctx, logger)
+
+			records := handler.Records()
+			if ok, diff := records.ContainsExact(testCase.want); !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", testCase.want, diff)
+			}
+		})
+	}
+}
+
+func TestWithLazyAttrs(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	ctx := slogctx.WithLazyAttrs(context.Background(), "display_name", func() slog.Value {
+		calls.Add(1)
+		return slog.StringValue("Jane Doe")
+	})
+
+	handler := slogmem.NewHandler(slog.LevelInfo)
+	logger := slog.New(slogctx.NewHandler(handler)).WithGroup("g1")
+
+	logger.DebugContext(ctx, "Disabled message")
+
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("lazy attr evaluated %d times for a disabled log, want: 0", got)
+	}
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			logger.InfoContext(ctx, "Test message")
+		}()
+	}
+
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("lazy attr evaluated %d times, want: 1", got)
+	}
+
+	want := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "Test message",
+		Attrs:   map[string]slog.Value{"g1.display_name": slog.StringValue("Jane Doe")},
+	}
+
+	if got := handler.Records().Count(want); got != 10 {
+		t.Errorf("expected 10 logged records to contain: %+v, got: %d", want, got)
+	}
+}
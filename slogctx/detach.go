@@ -0,0 +1,52 @@
+package slogctx
+
+import "context"
+
+// Detach returns a new [context.Context] that is never canceled and has no
+// deadline, carrying only the attributes added to ctx via [WithAttrs],
+// [WithRootAttrs] and [WithGroupedAttrs], the [slog.Logger] added via
+// [WithLogger], the baggage added via [WithBaggage] and the start time added
+// via [WithStartTime]. This is helpful when starting background work that must
+// outlive the request that started it while keeping its logs correlated with
+// the request.
+//
+// The values stored in ctx under each of the given keys are also copied so
+// that an [Extractor], such as one created with [NewValueExtractor], can still
+// find them. The log buffer added via [WithLogBuffer] is deliberately not
+// copied as it belongs to the lifetime of ctx.
+func Detach(ctx context.Context, keys ...any) context.Context {
+	detached := context.Background()
+	if ctx == nil {
+		return detached
+	}
+
+	for _, key := range append([]any{ctxKeyWithAttrs{}, ctxKeyWithRootAttrs{}, ctxKeyWithGroupedAttrs{}, ctxKeyLogger{}, ctxKeyBaggage{}, ctxKeyStartTime{}}, keys...) {
+		if value := ctx.Value(key); value != nil {
+			detached = context.WithValue(detached, key, value)
+		}
+	}
+
+	return detached
+}
+
+// Go calls fn in a new goroutine with a [context.Context] created by calling
+// [Detach] with ctx and the given keys. The returned function waits for fn to
+// return and reports its error, in the same way as errgroup.Group.Wait. It is
+// safe to call the returned function more than once.
+func Go(ctx context.Context, fn func(ctx context.Context) error, keys ...any) func() error {
+	detached := Detach(ctx, keys...)
+	done := make(chan struct{})
+
+	var err error
+
+	go func() {
+		defer close(done)
+
+		err = fn(detached)
+	}()
+
+	return func() error {
+		<-done
+		return err
+	}
+}
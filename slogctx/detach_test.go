@@ -0,0 +1,111 @@
+package slogctx_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestDetach(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	ctxHandler := slogctx.NewHandler(handler)
+	ctxHandler.AddExtractors(slogctx.NewValueAttrExtractor[string](ctxKeyTenant{}, "tenant"), slogctx.NewBaggageExtractor(), slogctx.NewTimingExtractor(slogctx.WithTimingClock(func() time.Time { return start.Add(time.Second) })))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	ctx = slogctx.WithLogger(ctx, slog.New(ctxHandler))
+	ctx = slogctx.WithRootAttrs(ctx, slog.String("r1", "v1"))
+	ctx = slogctx.WithAttrs(ctx, slog.String("p1", "v1"))
+	ctx = slogctx.WithBaggage(ctx, "user_id=42")
+	ctx = slogctx.WithStartTime(ctx, start)
+	ctx = context.WithValue(ctx, ctxKeyTenant{}, "acme")
+	ctx = context.WithValue(ctx, ctxKeyUser{}, &user{ID: 1, Name: "Jane"})
+
+	detached := slogctx.Detach(ctx, ctxKeyTenant{})
+
+	cancel()
+
+	if err := detached.Err(); err != nil {
+		t.Errorf("expected detached context not to be canceled, got: %v", err)
+	}
+
+	if _, ok := detached.Deadline(); ok {
+		t.Error("expected detached context not to have a deadline")
+	}
+
+	if detached.Value(ctxKeyUser{}) != nil {
+		t.Error("expected detached context not to carry values for keys that were not given")
+	}
+
+	slogctx.Logger(detached).InfoContext(detached, "Test message")
+
+	want := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "Test message",
+		Attrs: map[string]slog.Value{
+			"r1":              slog.StringValue("v1"),
+			"p1":              slog.StringValue("v1"),
+			"tenant":          slog.StringValue("acme"),
+			"baggage_user_id": slog.StringValue("42"),
+			"elapsed":         slog.DurationValue(time.Second),
+		},
+	}
+
+	if ok, diff := handler.Records().ContainsExact(want); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", want, diff)
+	}
+}
+
+func TestGo(t *testing.T) {
+	t.Parallel()
+
+	errWork := errors.New("work failed")
+	handler := slogmem.NewHandler(slog.LevelDebug)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = slogctx.WithLogger(ctx, slog.New(slogctx.NewHandler(handler)))
+	ctx = slogctx.WithAttrs(ctx, slog.String("request_id", "abc"))
+
+	started := make(chan struct{})
+
+	wait := slogctx.Go(ctx, func(ctx context.Context) error {
+		<-started
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		slogctx.Logger(ctx).InfoContext(ctx, "Background work")
+
+		return errWork
+	})
+
+	cancel()
+	close(started)
+
+	if err := wait(); !errors.Is(err, errWork) {
+		t.Errorf("expected wait to return: %v, got: %v", errWork, err)
+	}
+
+	if err := wait(); !errors.Is(err, errWork) {
+		t.Errorf("expected a second wait to return: %v, got: %v", errWork, err)
+	}
+
+	want := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "Background work",
+		Attrs:   map[string]slog.Value{"request_id": slog.StringValue("abc")},
+	}
+
+	if ok, diff := handler.Records().ContainsExact(want); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", want, diff)
+	}
+}
@@ -2,9 +2,19 @@ package slogctx
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"reflect"
 )
 
+// ExtractorErrorKey is the key of the attr added in place of the attrs of a
+// failing [Extractor] when using the [AddExtractorErrorAttr] policy.
+const ExtractorErrorKey = "extractor_error"
+
+// ErrExtractorPanicked is returned, wrapped, when an [Extractor] panics.
+var ErrExtractorPanicked = errors.New("extractor panicked")
+
 // An Extractor extracts [slog.Attr] values from a [context.Context].
 type Extractor interface {
 	Extract(ctx context.Context) []slog.Attr
@@ -21,6 +31,53 @@ func (f ExtractorFunc) Extract(ctx context.Context) []slog.Attr {
 	return f(ctx)
 }
 
+// An ErrorExtractor is an [Extractor] that can report a failure to extract
+// [slog.Attr] values from a [context.Context]. When added to a [Handler], the
+// error is handled according to the [ExtractorErrorPolicy] of the [Handler].
+type ErrorExtractor interface {
+	Extractor
+	ExtractWithError(ctx context.Context) ([]slog.Attr, error)
+}
+
+// Ensure that [ErrorExtractorFunc] implements [ErrorExtractor].
+var _ ErrorExtractor = ErrorExtractorFunc(func(_ context.Context) ([]slog.Attr, error) { return nil, nil })
+
+// ErrorExtractorFunc allows a function that can fail to be used as an [ErrorExtractor].
+type ErrorExtractorFunc func(ctx context.Context) ([]slog.Attr, error)
+
+// Extract calls the underlying function to implement the [Extractor] interface.
+// Any attrs are dropped if the function returns an error.
+func (f ErrorExtractorFunc) Extract(ctx context.Context) []slog.Attr {
+	attrs, err := f(ctx)
+	if err != nil {
+		return nil
+	}
+
+	return attrs
+}
+
+// ExtractWithError calls the underlying function to implement the [ErrorExtractor] interface.
+func (f ErrorExtractorFunc) ExtractWithError(ctx context.Context) ([]slog.Attr, error) {
+	return f(ctx)
+}
+
+// safeExtract runs the [Extractor], using ExtractWithError for an
+// [ErrorExtractor], and converts any panic into an error wrapping
+// [ErrExtractorPanicked].
+func safeExtract(ctx context.Context, extractor Extractor) (attrs []slog.Attr, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			attrs, err = nil, fmt.Errorf("%w: %v", ErrExtractorPanicked, r)
+		}
+	}()
+
+	if errorExtractor, ok := extractor.(ErrorExtractor); ok {
+		return errorExtractor.ExtractWithError(ctx)
+	}
+
+	return extractor.Extract(ctx), nil
+}
+
 // newCtxExtractor creates an [ExtractorFunc] that uses one of the allowed keys to extract
 // [slog.Attr] values from the given [context.Context].
 func newCtxExtractor[K ctxKeyWithAttrs | ctxKeyWithRootAttrs](key K) ExtractorFunc {
@@ -32,3 +89,80 @@ func newCtxExtractor[K ctxKeyWithAttrs | ctxKeyWithRootAttrs](key K) ExtractorFu
 		return nil
 	}
 }
+
+// RootExtractor is an [Extractor] whose attrs are added to the root of the log
+// record rather than the current group when it is added to a [Handler] via
+// [Handler.AddExtractors]. It is created with [ExtractAtRoot].
+type RootExtractor struct {
+	Extractor
+}
+
+// ExtractAtRoot wraps the given [Extractor] so that [Handler.AddExtractors]
+// adds it to the [Handler] in the same way as [Handler.AddRootAttrExtractors].
+// Any [Extractor] can be wrapped, for example:
+//
+//	handler.AddExtractors(slogctx.ExtractAtRoot(slogctx.NewTimingExtractor()))
+func ExtractAtRoot(extractor Extractor) RootExtractor {
+	return RootExtractor{Extractor: extractor}
+}
+
+// ValueExtractor is an [Extractor] that extracts [slog.Attr] values from a
+// single typed value stored in a [context.Context]. It is created with
+// [NewValueExtractor] or [NewValueAttrExtractor].
+type ValueExtractor struct {
+	extract ExtractorFunc
+}
+
+// Ensure that [ValueExtractor] implements [Extractor].
+var _ Extractor = ValueExtractor{} //nolint:exhaustruct // Compile time implementation check.
+
+// NewValueExtractor creates a [ValueExtractor] that looks up the value stored
+// under key in the [context.Context] and converts it to attrs with toAttrs.
+// No attrs are extracted when the value is missing, is not of type T or is nil.
+func NewValueExtractor[K comparable, T any](key K, toAttrs func(T) []slog.Attr) ValueExtractor {
+	return ValueExtractor{
+		extract: func(ctx context.Context) []slog.Attr {
+			value, ok := ctx.Value(key).(T)
+			if !ok || isNil(value) {
+				return nil
+			}
+
+			return toAttrs(value)
+		},
+	}
+}
+
+// NewValueAttrExtractor creates a [ValueExtractor] that looks up the value
+// stored under key in the [context.Context] and extracts it as a single attr
+// with the given name. No attrs are extracted when the value is missing, is not
+// of type T or is nil. T is the first type parameter so that the type of the
+// key can be inferred, for example: NewValueAttrExtractor[string](key, "name").
+func NewValueAttrExtractor[T any, K comparable](key K, name string) ValueExtractor {
+	return NewValueExtractor(key, func(value T) []slog.Attr {
+		return []slog.Attr{slog.Any(name, value)}
+	})
+}
+
+// Extract implements the [Extractor] interface.
+func (e ValueExtractor) Extract(ctx context.Context) []slog.Attr {
+	if e.extract == nil {
+		return nil
+	}
+
+	return e.extract(ctx)
+}
+
+// isNil reports whether value is nil, including typed nil values held in an
+// interface such as a nil pointer.
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	switch v := reflect.ValueOf(value); v.Kind() { //nolint:exhaustive // Only nillable kinds need checking.
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
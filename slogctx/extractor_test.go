@@ -0,0 +1,181 @@
+package slogctx_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+type (
+	ctxKeyTenant struct{}
+	ctxKeyUser   struct{}
+
+	user struct {
+		ID   int
+		Name string
+	}
+)
+
+func TestValueExtractor(t *testing.T) {
+	t.Parallel()
+
+	userExtractor := slogctx.NewValueExtractor(ctxKeyUser{}, func(u *user) []slog.Attr {
+		return []slog.Attr{slog.Group("user", slog.Int("id", u.ID), slog.String("name", u.Name))}
+	})
+	tenantExtractor := slogctx.ExtractAtRoot(slogctx.NewValueAttrExtractor[string](ctxKeyTenant{}, "tenant"))
+
+	testCases := map[string]struct {
+		ctx  context.Context
+		want slogmem.RecordQuery
+	}{
+		"extracts attrs from values in the context": {
+			ctx: context.WithValue(context.WithValue(context.Background(), ctxKeyTenant{}, "acme"), ctxKeyUser{}, &user{ID: 1, Name: "Jane"}),
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"tenant":       slog.StringValue("acme"),
+					"g1.e1":        slog.IntValue(123),
+					"g1.user.id":   slog.IntValue(1),
+					"g1.user.name": slog.StringValue("Jane"),
+				},
+			},
+		},
+		"extracts nothing when the values are missing": {
+			ctx: context.Background(),
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"g1.e1": slog.IntValue(123)},
+			},
+		},
+		"extracts nothing when the values are nil": {
+			ctx: context.WithValue(context.Background(), ctxKeyUser{}, (*user)(nil)),
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"g1.e1": slog.IntValue(123)},
+			},
+		},
+		"extracts nothing when the values are of a different type": {
+			ctx: context.WithValue(context.Background(), ctxKeyTenant{}, 123),
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"g1.e1": slog.IntValue(123)},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := slogmem.NewHandler(slog.LevelDebug)
+			ctxHandler := slogctx.NewHandler(handler)
+			ctxHandler.AddExtractors(userExtractor, tenantExtractor)
+
+			slog.New(ctxHandler).WithGroup("g1").InfoContext(tc.ctx, "Test message", slog.Int("e1", 123))
+
+			if ok, diff := handler.Records().ContainsExact(tc.want); !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestHandlerExtractorFailures(t *testing.T) {
+	t.Parallel()
+
+	errExtraction := errors.New("extraction failed")
+
+	panickingExtractor := slogctx.ExtractorFunc(func(ctx context.Context) []slog.Attr {
+		return []slog.Attr{slog.String("name", ctx.Value(ctxKeyUser{}).(*user).Name)}
+	})
+	erroringExtractor := slogctx.ErrorExtractorFunc(func(_ context.Context) ([]slog.Attr, error) {
+		return []slog.Attr{slog.String("partial", "value")}, errExtraction
+	})
+
+	testCases := map[string]struct {
+		extractor slogctx.Extractor
+		options   []slogctx.HandlerOption
+		want      slogmem.RecordQuery
+		wantErr   error
+	}{
+		"drops the attrs of an extractor that panics": {
+			extractor: panickingExtractor,
+			options:   nil,
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"e1": slog.IntValue(123)},
+			},
+			wantErr: slogctx.ErrExtractorPanicked,
+		},
+		"drops the attrs of an extractor that errors": {
+			extractor: erroringExtractor,
+			options:   []slogctx.HandlerOption{slogctx.WithExtractorErrorPolicy(slogctx.DropExtractorAttrs)},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"e1": slog.IntValue(123)},
+			},
+			wantErr: errExtraction,
+		},
+		"handles the error of an extractor added at the root": {
+			extractor: slogctx.ExtractAtRoot(erroringExtractor),
+			options:   []slogctx.HandlerOption{slogctx.WithExtractorErrorPolicy(slogctx.AddExtractorErrorAttr)},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"e1":                      slog.IntValue(123),
+					slogctx.ExtractorErrorKey: slog.StringValue("extraction failed"),
+				},
+			},
+			wantErr: errExtraction,
+		},
+		"adds a diagnostic attr for an extractor that errors": {
+			extractor: erroringExtractor,
+			options:   []slogctx.HandlerOption{slogctx.WithExtractorErrorPolicy(slogctx.AddExtractorErrorAttr)},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"e1":                      slog.IntValue(123),
+					slogctx.ExtractorErrorKey: slog.StringValue("extraction failed"),
+				},
+			},
+			wantErr: errExtraction,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotErr error
+
+			handler := slogmem.NewHandler(slog.LevelDebug)
+			options := append([]slogctx.HandlerOption{
+				slogctx.WithExtractorErrorCallback(func(_ context.Context, err error) { gotErr = err }),
+			}, tc.options...)
+			ctxHandler := slogctx.NewHandler(handler, options...)
+			ctxHandler.AddExtractors(tc.extractor)
+
+			slog.New(ctxHandler).InfoContext(context.Background(), "Test message", slog.Int("e1", 123))
+
+			if ok, diff := handler.Records().ContainsExact(tc.want); !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", tc.want, diff)
+			}
+
+			if !errors.Is(gotErr, tc.wantErr) {
+				t.Errorf("expected error callback to be called with: %v, got: %v", tc.wantErr, gotErr)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ type Handler struct {
 	persistentAttrs    internal.AttrGroupTree
 	attrExtractors     []Extractor
 	rootAttrExtractors []Extractor
+	opts               handlerOptions
 }
 
 // Ensure that our [Handler] implements the [slog.Handler] interface.
@@ -28,13 +29,15 @@ var _ slog.Handler = &Handler{} //nolint:exhaustruct // Compile time implementat
 // [WithRootAttrs] and [WithAttrs].
 //
 // All extracted attributes will be passed to the wrapped [slog.Handler] for
-// further processing.
-func NewHandler(wrapped slog.Handler) *Handler {
+// further processing. An [Extractor] that panics will never cause the log to be
+// lost, see [WithExtractorErrorPolicy] for how failures are handled.
+func NewHandler(wrapped slog.Handler, options ...HandlerOption) *Handler {
 	h := &Handler{
 		Handler:            wrapped,
 		persistentAttrs:    internal.NewAttrGroupTree(),
 		attrExtractors:     make([]Extractor, 0, 1),
 		rootAttrExtractors: make([]Extractor, 0, 1),
+		opts:               mapHandlerOptionsToDefaults(options),
 	}
 
 	h.AddAttrExtractors(newCtxExtractor(ctxKeyWithAttrs{}))
@@ -52,6 +55,7 @@ func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 		persistentAttrs:    h.persistentAttrs.WithAttrs(attrs),
 		attrExtractors:     h.attrExtractors,
 		rootAttrExtractors: h.rootAttrExtractors,
+		opts:               h.opts,
 	}
 }
 
@@ -64,6 +68,7 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 		persistentAttrs:    h.persistentAttrs.WithGroup(name),
 		attrExtractors:     h.attrExtractors,
 		rootAttrExtractors: h.rootAttrExtractors,
+		opts:               h.opts,
 	}
 }
 
@@ -74,6 +79,20 @@ func (h *Handler) AddAttrExtractors(extractors ...Extractor) {
 	h.attrExtractors = append(h.attrExtractors, extractors...)
 }
 
+// AddExtractors adds the given list of [Extractor]s to the [Handler], placing
+// the attrs of each [RootExtractor] at the root of the log record and the attrs
+// of any other [Extractor] within the current group.
+func (h *Handler) AddExtractors(extractors ...Extractor) {
+	for _, extractor := range extractors {
+		if rooted, ok := extractor.(RootExtractor); ok {
+			h.AddRootAttrExtractors(rooted.Extractor)
+			continue
+		}
+
+		h.AddAttrExtractors(extractor)
+	}
+}
+
 // AddRootAttrExtractors adds the given list of [Extractor]s
 // to the list of [Extractor]s that will run before all other attrs
 // have been added to the log record adding them to the root of the
@@ -83,7 +102,7 @@ func (h *Handler) AddRootAttrExtractors(extractors ...Extractor) {
 }
 
 // Handle will extract attributes from [context.Context] where they have been
-// added via the functions [WithRootAttrs] and [WithAttrs]. All
+// added via the functions [WithRootAttrs], [WithGroupedAttrs] and [WithAttrs]. All
 // extracted attributes will be passed to the embedded logger for further
 // processing.
 func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
@@ -95,7 +114,7 @@ func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
 	})
 
 	for _, extractor := range h.attrExtractors {
-		if attrs := extractor.Extract(ctx); attrs != nil {
+		if attrs := h.extract(ctx, extractor); attrs != nil {
 			recordAttrs = append(recordAttrs, attrs...)
 		}
 	}
@@ -104,13 +123,24 @@ func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
 	orderedRecordedAttrs := h.persistentAttrs.WithAttrs(recordAttrs).History()
 
 	for _, extractor := range h.rootAttrExtractors {
-		if attrs := extractor.Extract(ctx); attrs != nil {
+		if attrs := h.extract(ctx, extractor); attrs != nil {
 			orderedRecordedAttrs.PushFront(attrs)
 		}
 	}
 
+	if groups, ok := ctx.Value(ctxKeyWithGroupedAttrs{}).([]slog.Attr); ok {
+		for _, group := range groups {
+			orderedRecordedAttrs.PushGroup(groupPath(group.Key), group.Value.Group())
+		}
+	}
+
 	record = slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
-	record.AddAttrs(orderedRecordedAttrs.DeduplicatedAttrs()...)
+
+	if h.opts.mergeGroups {
+		record.AddAttrs(orderedRecordedAttrs.MergedAttrs()...)
+	} else {
+		record.AddAttrs(orderedRecordedAttrs.DeduplicatedAttrs()...)
+	}
 
 	if err := h.Handler.Handle(ctx, record); err != nil {
 		return fmt.Errorf("passing record to inner handler: %w", err)
@@ -118,3 +148,23 @@ func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
 
 	return nil
 }
+
+// extract runs the [Extractor], recovering from any panic. When the [Extractor]
+// panics or returns an error, the configured [ExtractorErrorPolicy] is applied
+// and the error callback is called.
+func (h *Handler) extract(ctx context.Context, extractor Extractor) []slog.Attr {
+	attrs, err := safeExtract(ctx, extractor)
+	if err == nil {
+		return attrs
+	}
+
+	if h.opts.extractorErrorCallback != nil {
+		h.opts.extractorErrorCallback(ctx, err)
+	}
+
+	if h.opts.extractorErrorPolicy == AddExtractorErrorAttr {
+		return []slog.Attr{slog.String(ExtractorErrorKey, err.Error())}
+	}
+
+	return nil
+}
@@ -10,13 +10,14 @@ import (
 	"log/slog"
 	"strings"
 	"testing"
-	"testing/slogtest"
 	"time"
 
 	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogctx/slogctxtest"
+	"github.com/nickbryan/slogutil/slogmem"
 )
 
-func TestHandlerafterSatisfiesSlogTestHarnessWhenActingAsLogMiddleware(t *testing.T) {
+func TestHandlerSatisfiesSlogTestHarnessWhenActingAsLogMiddleware(t *testing.T) {
 	t.Parallel()
 
 	for _, test := range []struct {
@@ -24,32 +25,65 @@ func TestHandlerafterSatisfiesSlogTestHarnessWhenActingAsLogMiddleware(t *testin
 		new   func(io.Writer) slog.Handler
 		parse func([]byte) (map[string]any, error)
 	}{
-		{"JSON", func(w io.Writer) slog.Handler { return slogctx.NewHandler(slog.NewJSONHandler(w, nil)) }, parseJSON},
-		{"Text", func(w io.Writer) slog.Handler { return slogctx.NewHandler(slog.NewTextHandler(w, nil)) }, parseText},
+		{"JSON", func(w io.Writer) slog.Handler { return slog.NewJSONHandler(w, nil) }, parseJSON},
+		{"Text", func(w io.Writer) slog.Handler { return slog.NewTextHandler(w, nil) }, parseText},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			t.Parallel()
 
-			var buf bytes.Buffer
+			var buf *bytes.Buffer
 
-			h := test.new(&buf)
+			newHandler := func(*testing.T) slog.Handler {
+				buf = &bytes.Buffer{}
+				return test.new(buf)
+			}
+
+			result := func(t *testing.T) map[string]any {
+				t.Helper()
 
-			results := func() []map[string]any {
-				ms, err := parseLines(buf.Bytes(), test.parse)
+				m, err := test.parse(buf.Bytes())
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				return ms
+				return m
 			}
 
-			if err := slogtest.TestHandler(h, results); err != nil {
-				t.Fatal(err)
-			}
+			slogctxtest.RunConformanceTests(t, newHandler, result)
 		})
 	}
 }
 
+func TestHandlerSatisfiesSlogTestHarnessWhenWrappingTheInMemoryHandler(t *testing.T) {
+	t.Parallel()
+
+	var handler *slogmem.Handler
+
+	newHandler := func(*testing.T) slog.Handler {
+		handler = slogmem.NewHandler(slog.LevelDebug)
+		return handler
+	}
+
+	result := func(t *testing.T) map[string]any {
+		t.Helper()
+
+		records := handler.Records().AsSliceOfNestedKeyValuePairs()
+		if len(records) != 1 {
+			t.Fatalf("expected a single record to be captured, got: %d", len(records))
+		}
+
+		// The in memory Handler captures the zero time for debugging purposes, so it is
+		// deleted here in order to pass the test harness.
+		if recordTime, ok := records[0][slog.TimeKey].(time.Time); ok && recordTime.IsZero() {
+			delete(records[0], slog.TimeKey)
+		}
+
+		return records[0]
+	}
+
+	slogctxtest.RunConformanceTests(t, newHandler, result)
+}
+
 type erroringHandler struct {
 	err error
 }
@@ -75,26 +109,6 @@ func TestHandlerReturnsErrorWhentheWrappedHandlerErrors(t *testing.T) {
 	}
 }
 
-func parseLines(src []byte, parse func([]byte) (map[string]any, error)) ([]map[string]any, error) {
-	//nolint: prealloc // Allocating length of lines will provide incorrect test results as it won't account for empty lines.
-	var records []map[string]any
-
-	for _, line := range bytes.Split(src, []byte{'\n'}) {
-		if len(line) == 0 {
-			continue
-		}
-
-		m, err := parse(line)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", string(line), err)
-		}
-
-		records = append(records, m)
-	}
-
-	return records, nil
-}
-
 func parseJSON(bs []byte) (map[string]any, error) {
 	var m map[string]any
 
@@ -152,3 +166,28 @@ func parseText(bs []byte) (map[string]any, error) {
 
 	return top, nil
 }
+
+func TestHandlerWithMergedGroupsMergesGroupsWithTheSameName(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	handler := slogctx.NewHandler(
+		slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return attr
+		}}),
+		slogctx.WithMergedGroups(true),
+	)
+
+	ctx := slogctx.WithRootAttrs(context.Background(), slog.Group("http", slog.String("method", "GET")))
+	slog.New(handler).WithGroup("http").InfoContext(ctx, "Test message", slog.Int("status", 200), slog.Int("status", 201))
+
+	want := `{"level":"INFO","msg":"Test message","http":{"method":"GET","status":200,"status#01":201}}`
+	if got := strings.TrimSpace(buf.String()); got != want {
+		t.Errorf("expected log line: %s, got: %s", want, got)
+	}
+}
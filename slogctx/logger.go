@@ -0,0 +1,112 @@
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+)
+
+type ctxKeyLogger struct{}
+
+// contextBoundHandler passes its bound [context.Context] to the wrapped
+// [slog.Handler] when a record is logged without one, such as via
+// [slog.Logger.Info] rather than [slog.Logger.InfoContext].
+type contextBoundHandler struct {
+	slog.Handler
+
+	ctx context.Context //nolint:containedctx // The context is bound so that it can be used when one is not passed.
+}
+
+// boundValuesContext is a [context.Context] that looks up each value in the
+// bound context when the passed context does not carry one.
+type boundValuesContext struct {
+	context.Context //nolint:containedctx // The passed context is extended with the values of the bound context.
+
+	bound context.Context //nolint:containedctx // The bound context is only used for value lookups.
+}
+
+// WithLogger returns a copy of ctx that carries the given [slog.Logger] so that
+// it can be retrieved with [Logger] by code further down the call stack.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, ctxKeyLogger{}, logger)
+}
+
+// Logger returns the [slog.Logger] added to ctx with [WithLogger], or
+// [slog.Default] when ctx does not carry one.
+//
+// The returned logger is bound to ctx, so attributes added via [WithAttrs] and
+// [WithRootAttrs] are included by a [Handler] even when logging without a
+// context, for example via [slog.Logger.Info]. When a context is passed, for
+// example via [slog.Logger.InfoContext], each value is looked up in that
+// context first and in ctx only when the passed context does not carry it.
+// A context derived from ctx therefore replaces its attributes rather than
+// adding them twice, while an unrelated context such as [context.TODO] keeps
+// the attributes of ctx.
+func Logger(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	logger, ok := ctx.Value(ctxKeyLogger{}).(*slog.Logger)
+	if !ok || logger == nil {
+		logger = slog.Default()
+	}
+
+	handler := logger.Handler()
+
+	// A logger that was itself returned by Logger is rebound rather than
+	// wrapped again so that passing it back to WithLogger does not nest
+	// handlers.
+	if bound, ok := handler.(*contextBoundHandler); ok {
+		handler = bound.Handler
+	}
+
+	return slog.New(&contextBoundHandler{Handler: handler, ctx: ctx})
+}
+
+// Enabled reports whether the wrapped handler is enabled for the given level.
+func (h *contextBoundHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.Handler.Enabled(h.context(ctx), level)
+}
+
+// Handle passes the record to the wrapped handler with the bound context if
+// one was not passed.
+func (h *contextBoundHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.Handler.Handle(h.context(ctx), record) //nolint:wrapcheck // The bound handler is transparent.
+}
+
+// WithAttrs returns a new contextBoundHandler wrapping the result of calling
+// WithAttrs on the wrapped handler.
+func (h *contextBoundHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextBoundHandler{Handler: h.Handler.WithAttrs(attrs), ctx: h.ctx}
+}
+
+// WithGroup returns a new contextBoundHandler wrapping the result of calling
+// WithGroup on the wrapped handler.
+func (h *contextBoundHandler) WithGroup(name string) slog.Handler {
+	return &contextBoundHandler{Handler: h.Handler.WithGroup(name), ctx: h.ctx}
+}
+
+// context returns the bound context when no context, or the bound context
+// itself, is passed. Otherwise the passed context is returned with the values
+// of the bound context as a fallback.
+func (h *contextBoundHandler) context(ctx context.Context) context.Context {
+	if ctx == nil || ctx == h.ctx {
+		return h.ctx
+	}
+
+	return boundValuesContext{Context: ctx, bound: h.ctx}
+}
+
+// Value returns the value for key from the passed context, or from the bound
+// context when the passed context does not carry one.
+func (c boundValuesContext) Value(key any) any {
+	if value := c.Context.Value(key); value != nil {
+		return value
+	}
+
+	return c.bound.Value(key)
+}
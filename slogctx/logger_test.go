@@ -0,0 +1,151 @@
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		log  func(ctx context.Context)
+		want slogmem.RecordQuery
+	}{
+		"logging without a context includes the attrs of the bound context": {
+			log: func(ctx context.Context) {
+				slogctx.Logger(ctx).Info("Test message", slog.Int("e1", 123)) //nolint:sloglint // Logging without a context is the behavior under test.
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"r1": slog.StringValue("v1"), "e1": slog.IntValue(123), "p1": slog.StringValue("v1")},
+			},
+		},
+		"logging with the same context does not duplicate the attrs": {
+			log: func(ctx context.Context) {
+				slogctx.Logger(ctx).InfoContext(ctx, "Test message")
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"r1": slog.StringValue("v1"), "p1": slog.StringValue("v1")},
+			},
+		},
+		"logging with a derived context uses the attrs of the derived context": {
+			log: func(ctx context.Context) {
+				logger := slogctx.Logger(ctx)
+				logger.InfoContext(slogctx.WithAttrs(ctx, slog.String("p2", "v2")), "Test message")
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"r1": slog.StringValue("v1"), "p1": slog.StringValue("v1"), "p2": slog.StringValue("v2")},
+			},
+		},
+		"logging with an unrelated context falls back to the attrs of the bound context": {
+			log: func(ctx context.Context) {
+				slogctx.Logger(ctx).InfoContext(context.TODO(), "Test message")
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"r1": slog.StringValue("v1"), "p1": slog.StringValue("v1")},
+			},
+		},
+		"logging with an unrelated context uses its attrs over those of the bound context": {
+			log: func(ctx context.Context) {
+				slogctx.Logger(ctx).InfoContext(slogctx.WithAttrs(context.TODO(), slog.String("p2", "v2")), "Test message")
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"r1": slog.StringValue("v1"), "p2": slog.StringValue("v2")},
+			},
+		},
+		"logging with a group keeps the bound context": {
+			log: func(ctx context.Context) {
+				slogctx.Logger(ctx).WithGroup("g1").With(slog.Int("w1", 1)).Info("Test message") //nolint:sloglint // Logging without a context is the behavior under test.
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"r1": slog.StringValue("v1"), "g1.w1": slog.IntValue(1), "g1.p1": slog.StringValue("v1")},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := slogmem.NewHandler(slog.LevelDebug)
+
+			ctx := slogctx.WithLogger(context.Background(), slog.New(slogctx.NewHandler(handler)))
+			ctx = slogctx.WithRootAttrs(ctx, slog.String("r1", "v1"))
+			ctx = slogctx.WithAttrs(ctx, slog.String("p1", "v1"))
+
+			tc.log(ctx)
+
+			if ok, diff := handler.Records().ContainsExact(tc.want); !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestLoggerDoesNotNestBoundHandlers(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+
+	ctx := slogctx.WithLogger(context.Background(), slog.New(slogctx.NewHandler(handler)))
+	ctx = slogctx.WithAttrs(ctx, slog.String("p1", "v1"))
+
+	for range 3 {
+		ctx = slogctx.WithLogger(ctx, slogctx.Logger(ctx))
+	}
+
+	ctx = slogctx.WithAttrs(ctx, slog.String("p2", "v2"))
+
+	slogctx.Logger(ctx).Info("Test message") //nolint:sloglint // Logging without a context is the behavior under test.
+
+	want := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "Test message",
+		Attrs:   map[string]slog.Value{"p1": slog.StringValue("v1"), "p2": slog.StringValue("v2")},
+	}
+
+	if ok, diff := handler.Records().ContainsExact(want); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", want, diff)
+	}
+}
+
+//nolint:paralleltest // The default logger is global so this test cannot run in parallel.
+func TestLoggerReturnsTheDefaultLoggerWhenAbsent(t *testing.T) {
+	for name, ctx := range map[string]context.Context{
+		"background context": context.Background(),
+		"nil context":        nil,
+		"nil logger":         slogctx.WithLogger(context.Background(), nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := slogmem.NewHandler(slog.LevelDebug)
+
+			defaultLogger := slog.Default()
+			slog.SetDefault(slog.New(handler))
+			t.Cleanup(func() { slog.SetDefault(defaultLogger) })
+
+			slogctx.Logger(ctx).Info("Test message") //nolint:sloglint // Logging without a context is the behavior under test.
+
+			want := slogmem.RecordQuery{Level: slog.LevelInfo, Message: "Test message", Attrs: nil}
+
+			if ok, diff := handler.Records().ContainsExact(want); !ok {
+				t.Errorf("expected the default logger to contain: %+v, got: %s", want, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package slogctx
+
+import "context"
+
+type (
+	// HandlerOption is an optional configuration value used to configure a [Handler].
+	HandlerOption func(*handlerOptions)
+
+	handlerOptions struct {
+		extractorErrorPolicy   ExtractorErrorPolicy
+		extractorErrorCallback func(ctx context.Context, err error)
+		mergeGroups            bool
+	}
+)
+
+// ExtractorErrorPolicy determines what a [Handler] does with the attrs of an
+// [Extractor] that panics or an [ErrorExtractor] that returns an error.
+type ExtractorErrorPolicy int
+
+const (
+	// DropExtractorAttrs drops any attrs returned by the failing [Extractor].
+	DropExtractorAttrs ExtractorErrorPolicy = iota
+	// AddExtractorErrorAttr drops any attrs returned by the failing [Extractor]
+	// and adds an attr with the [ExtractorErrorKey] key and the error message in
+	// their place.
+	AddExtractorErrorAttr
+)
+
+// WithExtractorErrorPolicy sets the [ExtractorErrorPolicy] of the [Handler].
+// The default is [DropExtractorAttrs].
+func WithExtractorErrorPolicy(policy ExtractorErrorPolicy) HandlerOption {
+	return func(o *handlerOptions) {
+		o.extractorErrorPolicy = policy
+	}
+}
+
+// WithExtractorErrorCallback sets a function that will be called with the
+// error each time an [Extractor] panics or an [ErrorExtractor] returns an
+// error. This is useful for reporting the error to an error tracker. The
+// callback is called in addition to applying the [ExtractorErrorPolicy]. The
+// default is nil.
+func WithExtractorErrorCallback(callback func(ctx context.Context, err error)) HandlerOption {
+	return func(o *handlerOptions) {
+		o.extractorErrorCallback = callback
+	}
+}
+
+// WithMergedGroups sets whether groups with the same key at the same group level
+// are merged into a single group rather than the keys of the later groups being
+// suffixed with #0x. For example, a logger with the group "http" that is passed
+// a slog.Group("http", ...) attr via [WithRootAttrs] will have a single "http"
+// group in the output. Duplicate keys within the merged groups are still
+// suffixed. The default is false.
+func WithMergedGroups(mergeGroups bool) HandlerOption {
+	return func(o *handlerOptions) {
+		o.mergeGroups = mergeGroups
+	}
+}
+
+func mapHandlerOptionsToDefaults(opts []HandlerOption) handlerOptions {
+	mappedDefaultOpts := handlerOptions{
+		extractorErrorPolicy:   DropExtractorAttrs,
+		extractorErrorCallback: nil,
+		mergeGroups:            false,
+	}
+
+	for _, opt := range opts {
+		opt(&mappedDefaultOpts)
+	}
+
+	return mappedDefaultOpts
+}
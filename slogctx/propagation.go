@@ -0,0 +1,370 @@
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// AttrsCarrierKey is the key of the [Carrier] entry holding the attributes
+	// added via [WithAttrs].
+	AttrsCarrierKey = "Slogctx-Attrs"
+	// RootAttrsCarrierKey is the key of the [Carrier] entry holding the
+	// attributes added via [WithRootAttrs].
+	RootAttrsCarrierKey = "Slogctx-Root-Attrs"
+)
+
+type (
+	// PropagationOption is an optional configuration value used to configure
+	// [ExportAttrs] and [ImportAttrs].
+	PropagationOption func(*propagationOptions)
+
+	propagationOptions struct {
+		allowedKeys []string
+		maxEntries  int
+		maxBytes    int
+	}
+
+	// propagatedAttr is a single attribute flattened to its dot separated path
+	// and string value.
+	propagatedAttr struct {
+		path  []string
+		value string
+	}
+)
+
+// Carrier stores the attributes exported by [ExportAttrs] so that they can be
+// sent across a process boundary and read back by [ImportAttrs].
+type Carrier interface {
+	// Get returns the value stored for key or an empty string if there is none.
+	Get(key string) string
+	// Set stores the value for key, replacing any existing value.
+	Set(key, value string)
+}
+
+// Ensure that our carriers implement the [Carrier] interface.
+var (
+	_ Carrier = HeaderCarrier{}
+	_ Carrier = MapCarrier{}
+)
+
+// HeaderCarrier allows [http.Header] to be used as a [Carrier].
+type HeaderCarrier http.Header
+
+// Get returns the first value of the header for key.
+func (hc HeaderCarrier) Get(key string) string {
+	return http.Header(hc).Get(key)
+}
+
+// Set sets the header for key to value.
+func (hc HeaderCarrier) Set(key, value string) {
+	http.Header(hc).Set(key, value)
+}
+
+// MapCarrier allows a map[string]string, such as message metadata, to be used
+// as a [Carrier].
+type MapCarrier map[string]string
+
+// Get returns the value stored for key.
+func (mc MapCarrier) Get(key string) string {
+	return mc[key]
+}
+
+// Set stores the value for key.
+func (mc MapCarrier) Set(key, value string) {
+	mc[key] = value
+}
+
+// WithAllowedKeys sets the attributes that are exported and imported to those
+// with the given dot separated paths, for example: "http.method". A group path
+// allows all attributes within the group. The default is to allow no
+// attributes as the [Carrier] of an inbound request or message is untrusted.
+// The built-in [slog.TimeKey], [slog.LevelKey], [slog.MessageKey] and
+// [slog.SourceKey] keys are never allowed at the top level so that they cannot
+// be forged.
+func WithAllowedKeys(keys ...string) PropagationOption {
+	return func(o *propagationOptions) {
+		o.allowedKeys = append(o.allowedKeys, keys...)
+	}
+}
+
+// WithMaxEntries sets the maximum number of attributes that are exported or
+// imported for each [Carrier] entry. Any attributes over the limit are dropped.
+// The default is 64.
+func WithMaxEntries(maxEntries int) PropagationOption {
+	return func(o *propagationOptions) {
+		o.maxEntries = maxEntries
+	}
+}
+
+// WithMaxBytes sets the maximum size in bytes of each encoded [Carrier] entry.
+// Any attributes that would take the entry over the limit are dropped when
+// exporting and an entry over the limit is ignored entirely when importing. The
+// default is 8192.
+func WithMaxBytes(maxBytes int) PropagationOption {
+	return func(o *propagationOptions) {
+		o.maxBytes = maxBytes
+	}
+}
+
+// ExportAttrs writes the attributes added to ctx via [WithAttrs] and
+// [WithRootAttrs] that are allowed by [WithAllowedKeys] to the carrier so that
+// they can be restored in another process with [ImportAttrs]. This would
+// typically be called by a client or message producer before sending a request
+// or message. Only the values of allowed attributes, or of groups that may
+// contain them, are resolved.
+//
+// The attributes are encoded in a similar way to W3C baggage, as a comma
+// separated list of percent encoded key=value pairs under the
+// [AttrsCarrierKey] and [RootAttrsCarrierKey] keys. Groups are flattened to
+// dot separated keys and all values are converted to strings.
+func ExportAttrs(ctx context.Context, carrier Carrier, options ...PropagationOption) {
+	if ctx == nil {
+		return
+	}
+
+	opts := mapPropagationOptionsToDefaults(options)
+
+	for carrierKey, ctxKey := range map[string]any{AttrsCarrierKey: ctxKeyWithAttrs{}, RootAttrsCarrierKey: ctxKeyWithRootAttrs{}} {
+		attrs, ok := ctx.Value(ctxKey).([]slog.Attr)
+		if !ok {
+			continue
+		}
+
+		if encoded := opts.encode(opts.flatten(nil, attrs)); encoded != "" {
+			carrier.Set(carrierKey, encoded)
+		}
+	}
+}
+
+// ImportAttrs returns a copy of ctx with the attributes written to the carrier
+// by [ExportAttrs] that are allowed by [WithAllowedKeys] added via [WithAttrs]
+// and [WithRootAttrs]. This would typically be called by middleware or a
+// message consumer when a request or message is received. Malformed entries in
+// the carrier and attributes that are not allowed are ignored, so nothing is
+// imported unless [WithAllowedKeys] is given.
+//
+// All imported values are strings and dot separated keys are restored as
+// groups.
+func ImportAttrs(ctx context.Context, carrier Carrier, options ...PropagationOption) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	opts := mapPropagationOptionsToDefaults(options)
+
+	if attrs := opts.decode(carrier.Get(RootAttrsCarrierKey)); len(attrs) > 0 {
+		ctx = WithRootAttrs(ctx, unflattenPropagatedAttrs(attrs)...)
+	}
+
+	if attrs := opts.decode(carrier.Get(AttrsCarrierKey)); len(attrs) > 0 {
+		ctx = WithAttrs(ctx, unflattenPropagatedAttrs(attrs)...)
+	}
+
+	return ctx
+}
+
+func mapPropagationOptionsToDefaults(opts []PropagationOption) propagationOptions {
+	const defaultMaxEntries, defaultMaxBytes = 64, 8192
+
+	mappedDefaultOpts := propagationOptions{
+		allowedKeys: nil,
+		maxEntries:  defaultMaxEntries,
+		maxBytes:    defaultMaxBytes,
+	}
+
+	for _, opt := range opts {
+		opt(&mappedDefaultOpts)
+	}
+
+	return mappedDefaultOpts
+}
+
+// encode joins the allowed attributes into a single entry, dropping any that
+// would exceed the limits.
+func (o propagationOptions) encode(attrs []propagatedAttr) string {
+	var (
+		builder strings.Builder
+		entries int
+	)
+
+	for _, attr := range attrs {
+		if entries >= o.maxEntries {
+			break
+		}
+
+		member := url.QueryEscape(strings.Join(attr.path, ".")) + "=" + url.QueryEscape(attr.value)
+		if builder.Len() > 0 {
+			member = "," + member
+		}
+
+		if builder.Len()+len(member) > o.maxBytes {
+			continue
+		}
+
+		builder.WriteString(member)
+
+		entries++
+	}
+
+	return builder.String()
+}
+
+// decode splits an entry into the allowed attributes, dropping any that are
+// malformed or exceed the limits.
+func (o propagationOptions) decode(encoded string) []propagatedAttr {
+	if encoded == "" || len(encoded) > o.maxBytes {
+		return nil
+	}
+
+	attrs := make([]propagatedAttr, 0)
+
+	for _, member := range strings.Split(encoded, ",") {
+		if len(attrs) >= o.maxEntries {
+			break
+		}
+
+		encodedKey, encodedValue, ok := strings.Cut(strings.TrimSpace(member), "=")
+		if !ok {
+			continue
+		}
+
+		key, err := url.QueryUnescape(encodedKey)
+		if err != nil || key == "" {
+			continue
+		}
+
+		value, err := url.QueryUnescape(encodedValue)
+		if err != nil {
+			continue
+		}
+
+		path := strings.Split(key, ".")
+		if slices.Contains(path, "") || !o.allowed(path) {
+			continue
+		}
+
+		attrs = append(attrs, propagatedAttr{path: path, value: value})
+	}
+
+	return attrs
+}
+
+// allowed reports whether the attribute at path is in the allow-list, either
+// directly or because one of its parent groups is. Paths starting with a
+// built-in key are never allowed.
+func (o propagationOptions) allowed(path []string) bool {
+	if len(path) == 0 || isBuiltinKey(path[0]) {
+		return false
+	}
+
+	for i := range path {
+		if slices.Contains(o.allowedKeys, strings.Join(path[:i+1], ".")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// traversable reports whether the attribute at path is allowed or is a group
+// that may contain allowed attributes, and so needs to be resolved.
+func (o propagationOptions) traversable(path []string) bool {
+	if len(path) == 0 {
+		return len(o.allowedKeys) > 0
+	}
+
+	if isBuiltinKey(path[0]) {
+		return false
+	}
+
+	if o.allowed(path) {
+		return true
+	}
+
+	groupPrefix := strings.Join(path, ".") + "."
+
+	return slices.ContainsFunc(o.allowedKeys, func(key string) bool {
+		return strings.HasPrefix(key, groupPrefix)
+	})
+}
+
+// flatten flattens the allowed attrs into their dot separated paths and string
+// values, omitting empty groups. Attrs that are not traversable are skipped
+// without being resolved.
+func (o propagationOptions) flatten(prefix []string, attrs []slog.Attr) []propagatedAttr {
+	flattened := make([]propagatedAttr, 0, len(attrs))
+
+	for _, attr := range attrs {
+		path := prefix
+		if attr.Key != "" {
+			path = append(slices.Clip(prefix), attr.Key)
+		}
+
+		if !o.traversable(path) {
+			continue
+		}
+
+		value := attr.Value.Resolve()
+
+		switch value.Kind() {
+		case slog.KindGroup:
+			flattened = append(flattened, o.flatten(path, value.Group())...)
+		case slog.KindTime:
+			if attr.Key != "" && o.allowed(path) {
+				flattened = append(flattened, propagatedAttr{path: path, value: value.Time().Format(time.RFC3339Nano)})
+			}
+		default:
+			if attr.Key != "" && o.allowed(path) {
+				flattened = append(flattened, propagatedAttr{path: path, value: value.String()})
+			}
+		}
+	}
+
+	return flattened
+}
+
+// isBuiltinKey reports whether key is one of the keys used by [slog.Handler]
+// implementations for the built-in attributes of a record.
+func isBuiltinKey(key string) bool {
+	switch key {
+	case slog.TimeKey, slog.LevelKey, slog.MessageKey, slog.SourceKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// unflattenPropagatedAttrs restores the flattened attrs as string attributes,
+// nesting those with dot separated paths within groups.
+func unflattenPropagatedAttrs(flattened []propagatedAttr) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(flattened))
+	groupIndexes := make(map[string]int)
+	groupMembers := make(map[string][]propagatedAttr)
+
+	for _, attr := range flattened {
+		if len(attr.path) == 1 {
+			attrs = append(attrs, slog.String(attr.path[0], attr.value))
+			continue
+		}
+
+		group := attr.path[0]
+		if _, ok := groupIndexes[group]; !ok {
+			groupIndexes[group] = len(attrs)
+			attrs = append(attrs, slog.Attr{Key: group, Value: slog.Value{}})
+		}
+
+		groupMembers[group] = append(groupMembers[group], propagatedAttr{path: attr.path[1:], value: attr.value})
+	}
+
+	for group, i := range groupIndexes {
+		attrs[i].Value = slog.GroupValue(unflattenPropagatedAttrs(groupMembers[group])...)
+	}
+
+	return attrs
+}
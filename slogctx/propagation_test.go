@@ -0,0 +1,186 @@
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestExportAndImportAttrs(t *testing.T) {
+	t.Parallel()
+
+	producerCtx := slogctx.WithRootAttrs(context.Background(), slog.String("request_id", "abc 123"))
+	producerCtx = slogctx.WithAttrs(producerCtx,
+		slog.String("tenant", "acme,inc"),
+		slog.Group("http", slog.String("method", "GET"), slog.Int("status", 200)),
+		slog.String("secret", "hunter2"),
+	)
+
+	allowAll := slogctx.WithAllowedKeys("request_id", "tenant", "http", "secret")
+
+	testCases := map[string]struct {
+		carrier slogctx.Carrier
+		options []slogctx.PropagationOption
+		want    slogmem.RecordQuery
+	}{
+		"restores no attrs without allowed keys": {
+			carrier: slogctx.HeaderCarrier(http.Header{}),
+			options: nil,
+			want:    slogmem.RecordQuery{Level: slog.LevelInfo, Message: "Test message", Attrs: nil},
+		},
+		"restores all allowed attrs through http headers": {
+			carrier: slogctx.HeaderCarrier(http.Header{}),
+			options: []slogctx.PropagationOption{allowAll},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"request_id":     slog.StringValue("abc 123"),
+					"g1.tenant":      slog.StringValue("acme,inc"),
+					"g1.http.method": slog.StringValue("GET"),
+					"g1.http.status": slog.StringValue("200"),
+					"g1.secret":      slog.StringValue("hunter2"),
+				},
+			},
+		},
+		"restores only allowed attrs through message metadata": {
+			carrier: slogctx.MapCarrier{},
+			options: []slogctx.PropagationOption{slogctx.WithAllowedKeys("request_id", "tenant", "http.method")},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"request_id":     slog.StringValue("abc 123"),
+					"g1.tenant":      slog.StringValue("acme,inc"),
+					"g1.http.method": slog.StringValue("GET"),
+				},
+			},
+		},
+		"drops attrs over the entry limit": {
+			carrier: slogctx.MapCarrier{},
+			options: []slogctx.PropagationOption{allowAll, slogctx.WithMaxEntries(1)},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"request_id": slog.StringValue("abc 123"),
+					"g1.tenant":  slog.StringValue("acme,inc"),
+				},
+			},
+		},
+		"drops attrs over the size limit": {
+			carrier: slogctx.MapCarrier{},
+			options: []slogctx.PropagationOption{allowAll, slogctx.WithMaxBytes(len("tenant=acme%2Cinc"))},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"g1.tenant": slog.StringValue("acme,inc")},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			slogctx.ExportAttrs(producerCtx, tc.carrier, tc.options...)
+
+			handler := slogmem.NewHandler(slog.LevelDebug)
+			consumerCtx := slogctx.ImportAttrs(context.Background(), tc.carrier, tc.options...)
+
+			slog.New(slogctx.NewHandler(handler)).WithGroup("g1").InfoContext(consumerCtx, "Test message")
+
+			if ok, diff := handler.Records().ContainsExact(tc.want); !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestImportAttrsIgnoresMalformedAndOversizedEntries(t *testing.T) {
+	t.Parallel()
+
+	carrier := slogctx.MapCarrier{
+		slogctx.AttrsCarrierKey:     "valid=yes,novalue,=empty,bad%zz=1,a..b=2",
+		slogctx.RootAttrsCarrierKey: "big=" + strings.Repeat("x", 100),
+	}
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	ctx := slogctx.ImportAttrs(context.Background(), carrier, slogctx.WithAllowedKeys("valid", "novalue", "bad", "a", "big"), slogctx.WithMaxBytes(64))
+
+	slog.New(slogctx.NewHandler(handler)).InfoContext(ctx, "Test message")
+
+	want := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "Test message",
+		Attrs:   map[string]slog.Value{"valid": slog.StringValue("yes")},
+	}
+
+	if ok, diff := handler.Records().ContainsExact(want); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", want, diff)
+	}
+}
+
+func TestImportAttrsRejectsBuiltinKeys(t *testing.T) {
+	t.Parallel()
+
+	carrier := slogctx.MapCarrier{
+		slogctx.AttrsCarrierKey:     "msg=forged,account.level=debug",
+		slogctx.RootAttrsCarrierKey: "level=ERROR,time=2024-01-01T00%3A00%3A00Z,source=main.go,user.id=42",
+	}
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	ctx := slogctx.ImportAttrs(context.Background(), carrier, slogctx.WithAllowedKeys("msg", "level", "time", "source", "user", "account"))
+
+	slog.New(slogctx.NewHandler(handler)).InfoContext(ctx, "Test message")
+
+	want := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "Test message",
+		Attrs:   map[string]slog.Value{"user.id": slog.StringValue("42"), "account.level": slog.StringValue("debug")},
+	}
+
+	if ok, diff := handler.Records().ContainsExact(want); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", want, diff)
+	}
+}
+
+func TestExportAttrsOnlyResolvesAllowedAttrs(t *testing.T) {
+	t.Parallel()
+
+	allowed := &countingLogValuer{value: slog.GroupValue(slog.String("id", "42"), slog.String("email", "jane@example.com"))}
+	notAllowed := &countingLogValuer{value: slog.StringValue("hunter2")}
+
+	ctx := slogctx.WithAttrs(context.Background(), slog.Any("user", allowed), slog.Any("secret", notAllowed))
+	carrier := slogctx.MapCarrier{}
+
+	slogctx.ExportAttrs(ctx, carrier, slogctx.WithAllowedKeys("user.id"))
+
+	if got, want := carrier[slogctx.AttrsCarrierKey], "user.id=42"; got != want {
+		t.Errorf("carrier[%q] want: %q, got: %q", slogctx.AttrsCarrierKey, want, got)
+	}
+
+	if allowed.calls != 1 {
+		t.Errorf("allowed LogValue() calls want: 1, got: %d", allowed.calls)
+	}
+
+	if notAllowed.calls != 0 {
+		t.Errorf("not allowed LogValue() calls want: 0, got: %d", notAllowed.calls)
+	}
+}
+
+// countingLogValuer is a [slog.LogValuer] that counts how often it is resolved.
+type countingLogValuer struct {
+	value slog.Value
+	calls int
+}
+
+func (v *countingLogValuer) LogValue() slog.Value {
+	v.calls++
+	return v.value
+}
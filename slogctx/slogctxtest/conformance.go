@@ -0,0 +1,31 @@
+// Package slogctxtest provides helpers for testing [slog.Handler]
+// implementations that are used with the slogctx package.
+package slogctxtest
+
+import (
+	"log/slog"
+	"testing"
+	"testing/slogtest"
+
+	"github.com/nickbryan/slogutil/slogctx"
+)
+
+// RunConformanceTests runs the [testing/slogtest] conformance suite against
+// the [slog.Handler] created by newHandler once it has been wrapped by a
+// [slogctx.Handler]. Each check is run as a subtest of t. This can be used to verify
+// that a custom [slog.Handler] continues to satisfy the rules of the
+// [slog.Handler] interface when used with this package.
+//
+// The newHandler function is called for each subtest and should return a new
+// handler. The result function is called after each subtest has logged a single
+// record and should return the record written by the handler as a nested map of
+// key value pairs, as described by [slogtest.Run].
+func RunConformanceTests(t *testing.T, newHandler func(t *testing.T) slog.Handler, result func(t *testing.T) map[string]any) {
+	t.Helper()
+
+	slogtest.Run(t, func(t *testing.T) slog.Handler {
+		t.Helper()
+
+		return slogctx.NewHandler(newHandler(t))
+	}, result)
+}
@@ -0,0 +1,128 @@
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// DeadlineRemainingKey is the key of the attr holding the time remaining
+	// until the deadline of the [context.Context].
+	DeadlineRemainingKey = "deadline_remaining"
+	// ContextErrorKey is the key of the attr holding the reason that the
+	// [context.Context] is done, either "canceled" or "deadline_exceeded".
+	ContextErrorKey = "ctx_error"
+	// ElapsedKey is the key of the attr holding the time elapsed since the start
+	// time added to the [context.Context] via [WithStartTime].
+	ElapsedKey = "elapsed"
+)
+
+type (
+	ctxKeyStartTime struct{}
+
+	// TimingOption is an optional configuration value used to configure a
+	// [TimingExtractor].
+	TimingOption func(*timingOptions)
+
+	timingOptions struct {
+		group string
+		now   func() time.Time
+	}
+)
+
+// TimingExtractor is an [Extractor] that extracts the remaining deadline,
+// cancellation state and elapsed time of a [context.Context] as [slog.Attr]
+// values. It is created with [NewTimingExtractor].
+type TimingExtractor struct {
+	opts timingOptions
+}
+
+// Ensure that [TimingExtractor] implements [Extractor].
+var _ Extractor = TimingExtractor{} //nolint:exhaustruct // Compile time implementation check.
+
+// WithTimingGroup places the extracted attrs within a group with the given
+// name. The default is to not group the attrs.
+func WithTimingGroup(name string) TimingOption {
+	return func(o *timingOptions) {
+		o.group = name
+	}
+}
+
+// WithTimingClock sets the function used to get the current time. The default
+// is [time.Now].
+func WithTimingClock(now func() time.Time) TimingOption {
+	return func(o *timingOptions) {
+		o.now = now
+	}
+}
+
+// NewTimingExtractor creates a [TimingExtractor] that extracts the following
+// attrs from a [context.Context], omitting those that do not apply:
+//
+//   - [DeadlineRemainingKey]: the duration until the deadline, negative once it
+//     has passed.
+//   - [ContextErrorKey]: "canceled" or "deadline_exceeded" once the
+//     [context.Context] is done.
+//   - [ElapsedKey]: the duration since the start time added via
+//     [WithStartTime] or [TimingMiddleware].
+func NewTimingExtractor(options ...TimingOption) TimingExtractor {
+	opts := timingOptions{
+		group: "",
+		now:   time.Now,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return TimingExtractor{opts: opts}
+}
+
+// WithStartTime returns a copy of ctx that carries the given start time so that
+// the elapsed time can be extracted by a [TimingExtractor].
+func WithStartTime(ctx context.Context, start time.Time) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, ctxKeyStartTime{}, start)
+}
+
+// TimingMiddleware adds the time that each request was received to the
+// request's [context.Context] via [WithStartTime].
+func TimingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithStartTime(r.Context(), time.Now())))
+	})
+}
+
+// Extract implements the [Extractor] interface.
+func (e TimingExtractor) Extract(ctx context.Context) []slog.Attr {
+	var attrs []slog.Attr
+
+	now := e.opts.now()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		attrs = append(attrs, slog.Duration(DeadlineRemainingKey, deadline.Sub(now)))
+	}
+
+	switch err := ctx.Err(); {
+	case errors.Is(err, context.DeadlineExceeded):
+		attrs = append(attrs, slog.String(ContextErrorKey, "deadline_exceeded"))
+	case errors.Is(err, context.Canceled):
+		attrs = append(attrs, slog.String(ContextErrorKey, "canceled"))
+	}
+
+	if start, ok := ctx.Value(ctxKeyStartTime{}).(time.Time); ok {
+		attrs = append(attrs, slog.Duration(ElapsedKey, now.Sub(start)))
+	}
+
+	if len(attrs) == 0 || e.opts.group == "" {
+		return attrs
+	}
+
+	return []slog.Attr{{Key: e.opts.group, Value: slog.GroupValue(attrs...)}}
+}
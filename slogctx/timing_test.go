@@ -0,0 +1,136 @@
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestTimingExtractor(t *testing.T) {
+	t.Parallel()
+
+	// The deadlines are relative to the real time that the test is run so that
+	// the contexts are only done when expected.
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	testCases := map[string]struct {
+		ctx     func(t *testing.T) context.Context
+		options []slogctx.TimingOption
+		atRoot  bool
+		want    slogmem.RecordQuery
+	}{
+		"extracts nothing from a context without timing information": {
+			ctx:     func(_ *testing.T) context.Context { return context.Background() },
+			options: nil,
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{},
+			},
+		},
+		"extracts the remaining deadline and elapsed time": {
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+
+				ctx, cancel := context.WithDeadline(context.Background(), now.Add(time.Hour))
+				t.Cleanup(cancel)
+
+				return slogctx.WithStartTime(ctx, now.Add(-time.Second))
+			},
+			options: nil,
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"g1." + slogctx.DeadlineRemainingKey: slog.DurationValue(time.Hour),
+					"g1." + slogctx.ElapsedKey:           slog.DurationValue(time.Second),
+				},
+			},
+		},
+		"extracts a canceled context into a group at the root": {
+			ctx: func(_ *testing.T) context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return ctx
+			},
+			options: []slogctx.TimingOption{slogctx.WithTimingGroup("timing")},
+			atRoot:  true,
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs:   map[string]slog.Value{"timing." + slogctx.ContextErrorKey: slog.StringValue("canceled")},
+			},
+		},
+		"extracts an exceeded deadline": {
+			ctx: func(t *testing.T) context.Context {
+				t.Helper()
+
+				ctx, cancel := context.WithDeadline(context.Background(), now.Add(-time.Minute))
+				t.Cleanup(cancel)
+
+				return ctx
+			},
+			options: nil,
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Test message",
+				Attrs: map[string]slog.Value{
+					"g1." + slogctx.DeadlineRemainingKey: slog.DurationValue(-time.Minute),
+					"g1." + slogctx.ContextErrorKey:      slog.StringValue("deadline_exceeded"),
+				},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := slogmem.NewHandler(slog.LevelDebug)
+			ctxHandler := slogctx.NewHandler(handler)
+
+			var extractor slogctx.Extractor = slogctx.NewTimingExtractor(append([]slogctx.TimingOption{slogctx.WithTimingClock(clock)}, tc.options...)...)
+			if tc.atRoot {
+				extractor = slogctx.ExtractAtRoot(extractor)
+			}
+
+			ctxHandler.AddExtractors(extractor)
+
+			slog.New(ctxHandler).WithGroup("g1").InfoContext(tc.ctx(t), "Test message")
+
+			if ok, diff := handler.Records().ContainsExact(tc.want); !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestTimingMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	ctxHandler := slogctx.NewHandler(handler)
+	ctxHandler.AddExtractors(slogctx.NewTimingExtractor())
+	logger := slog.New(ctxHandler)
+
+	server := slogctx.TimingMiddleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		logger.InfoContext(r.Context(), "Test message")
+	}))
+
+	server.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if count := handler.Records().Where(func(record slogmem.LoggedRecord) bool {
+		elapsed, ok := record.Attr(slogctx.ElapsedKey)
+		return ok && elapsed.Kind() == slog.KindDuration && elapsed.Duration() >= 0
+	}).Len(); count != 1 {
+		t.Errorf("expected one record with a non-negative elapsed time, got: %d", count)
+	}
+}
@@ -0,0 +1,115 @@
+package slogmem
+
+import (
+	"iter"
+	"log/slog"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// All returns an iterator over a snapshot of the captured records in the order
+// that they were logged.
+func (lr *LoggedRecords) All() iter.Seq[LoggedRecord] {
+	records := lr.snapshot()
+
+	return func(yield func(LoggedRecord) bool) {
+		for _, record := range records {
+			if !yield(record) {
+				return
+			}
+		}
+	}
+}
+
+// Where returns a new LoggedRecords containing a snapshot of the records that
+// satisfy the given predicate. The returned LoggedRecords can be queried and
+// filtered further, allowing filters to be composed:
+//
+//	records.ByLevel(slog.LevelError).Where(func(r slogmem.LoggedRecord) bool {
+//		v, ok := r.Attr("tenant")
+//		return ok && v.String() == "acme"
+//	})
+func (lr *LoggedRecords) Where(predicate func(LoggedRecord) bool) *LoggedRecords {
+	filtered := make([]LoggedRecord, 0)
+
+	for record := range lr.All() {
+		if predicate(record) {
+			filtered = append(filtered, record)
+		}
+	}
+
+	return NewLoggedRecords(filtered)
+}
+
+// ByLevel returns a new LoggedRecords containing a snapshot of the records that
+// were logged at the given [slog.Level].
+func (lr *LoggedRecords) ByLevel(level slog.Level) *LoggedRecords {
+	return lr.Where(func(record LoggedRecord) bool {
+		return record.Level == level
+	})
+}
+
+// ByMessage returns a new LoggedRecords containing a snapshot of the records
+// that were logged with the given message.
+func (lr *LoggedRecords) ByMessage(msg string) *LoggedRecords {
+	return lr.Where(func(record LoggedRecord) bool {
+		return record.Message == msg
+	})
+}
+
+// Count returns the number of records that match the given [RecordQuery]. A
+// loose match is performed on the attributes in the query in the same way as
+// [LoggedRecords.Contains].
+func (lr *LoggedRecords) Count(query RecordQuery) int {
+	var (
+		count          int
+		opts           = containsOpts(query)
+		flattenedQuery = flattenRecordQuery(query)
+		records        = lr.snapshot()
+	)
+
+	for i, flattenedRecord := range flattenRecords(records) {
+		if compareMetadata(query, records[i]) == "" && cmp.Equal(flattenedQuery, flattenedRecord, opts...) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Attr returns the value of the attribute at the given dot separated path. For
+// example: if an attribute was written as `slog.Group("group",
+// slog.String("key", "value"))` then the path would be "group.key". The
+// returned bool reports whether the attribute was found.
+func (r LoggedRecord) Attr(path string) (slog.Value, bool) {
+	attrs := r.Attrs
+	keys := strings.Split(path, ".")
+
+	for i, key := range keys {
+		found := false
+
+		for _, attr := range attrs {
+			if attr.Key != key {
+				continue
+			}
+
+			if i == len(keys)-1 {
+				return attr.Value, true
+			}
+
+			if attr.Value.Kind() == slog.KindGroup {
+				attrs = attr.Value.Group()
+				found = true
+			}
+
+			break
+		}
+
+		if !found {
+			break
+		}
+	}
+
+	return slog.Value{}, false
+}
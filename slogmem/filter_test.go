@@ -0,0 +1,175 @@
+package slogmem_test
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func newFilterTestRecords() *slogmem.LoggedRecords {
+	now := time.Now()
+
+	return slogmem.NewLoggedRecords([]slogmem.LoggedRecord{
+		{Time: now, Level: slog.LevelInfo, Message: "request started", Attrs: []slog.Attr{slog.String("tenant", "acme")}},
+		{Time: now, Level: slog.LevelError, Message: "request failed", Attrs: []slog.Attr{slog.String("tenant", "acme"), slog.Group("http", slog.Int("status", 500))}},
+		{Time: now, Level: slog.LevelError, Message: "request failed", Attrs: []slog.Attr{slog.String("tenant", "globex"), slog.Group("http", slog.Int("status", 502))}},
+		{Time: now, Level: slog.LevelInfo, Message: "request finished", Attrs: []slog.Attr{slog.String("tenant", "globex")}},
+	})
+}
+
+func TestLoggedRecordsAll(t *testing.T) {
+	t.Parallel()
+
+	var got []string
+	for record := range newFilterTestRecords().All() {
+		got = append(got, record.Message)
+	}
+
+	want := []string{"request started", "request failed", "request failed", "request finished"}
+	if !slices.Equal(want, got) {
+		t.Errorf("All() messages want: %v, got: %v", want, got)
+	}
+}
+
+func TestLoggedRecordsFilters(t *testing.T) {
+	t.Parallel()
+
+	isTenant := func(tenant string) func(slogmem.LoggedRecord) bool {
+		return func(record slogmem.LoggedRecord) bool {
+			value, ok := record.Attr("tenant")
+			return ok && value.String() == tenant
+		}
+	}
+
+	testCases := map[string]struct {
+		filter  func(records *slogmem.LoggedRecords) *slogmem.LoggedRecords
+		wantLen int
+	}{
+		"ByLevel returns only records at the given level": {
+			filter:  func(records *slogmem.LoggedRecords) *slogmem.LoggedRecords { return records.ByLevel(slog.LevelError) },
+			wantLen: 2,
+		},
+		"ByMessage returns only records with the given message": {
+			filter: func(records *slogmem.LoggedRecords) *slogmem.LoggedRecords {
+				return records.ByMessage("request started")
+			},
+			wantLen: 1,
+		},
+		"Where returns only records that satisfy the predicate": {
+			filter:  func(records *slogmem.LoggedRecords) *slogmem.LoggedRecords { return records.Where(isTenant("globex")) },
+			wantLen: 2,
+		},
+		"filters compose": {
+			filter: func(records *slogmem.LoggedRecords) *slogmem.LoggedRecords {
+				return records.ByLevel(slog.LevelError).Where(isTenant("acme"))
+			},
+			wantLen: 1,
+		},
+		"returns an empty LoggedRecords when nothing matches": {
+			filter:  func(records *slogmem.LoggedRecords) *slogmem.LoggedRecords { return records.ByLevel(slog.LevelDebug) },
+			wantLen: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.filter(newFilterTestRecords()).Len(); got != tc.wantLen {
+				t.Errorf("filtered records Len() want: %d, got: %d", tc.wantLen, got)
+			}
+		})
+	}
+
+	t.Run("filtered records support queries", func(t *testing.T) {
+		t.Parallel()
+
+		filtered := newFilterTestRecords().ByLevel(slog.LevelError).Where(isTenant("acme"))
+
+		query := slogmem.RecordQuery{
+			Level:   slog.LevelError,
+			Message: "request failed",
+			Attrs:   map[string]slog.Value{"http.status": slog.IntValue(500)},
+		}
+
+		if ok, diff := filtered.Contains(query); !ok {
+			t.Errorf("filtered.Contains(%+v) returned false, diff: %s", query, diff)
+		}
+
+		query.Attrs = map[string]slog.Value{"http.status": slog.IntValue(502)}
+
+		if ok, _ := filtered.Contains(query); ok {
+			t.Errorf("filtered.Contains(%+v) returned true for a record that was filtered out", query)
+		}
+	})
+}
+
+func TestLoggedRecordsCount(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		query slogmem.RecordQuery
+		want  int
+	}{
+		"counts all records matching the message and level": {
+			query: slogmem.RecordQuery{Level: slog.LevelError, Message: "request failed"},
+			want:  2,
+		},
+		"counts records matching the queried attrs": {
+			query: slogmem.RecordQuery{Level: slog.LevelError, Message: "request failed", Attrs: map[string]slog.Value{"tenant": slog.StringValue("acme")}},
+			want:  1,
+		},
+		"returns zero when no records match": {
+			query: slogmem.RecordQuery{Level: slog.LevelDebug, Message: "request failed"},
+			want:  0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := newFilterTestRecords().Count(tc.query); got != tc.want {
+				t.Errorf("Count(%+v) want: %d, got: %d", tc.query, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLoggedRecordAttr(t *testing.T) {
+	t.Parallel()
+
+	record := slogmem.LoggedRecord{
+		Attrs: []slog.Attr{
+			slog.String("key", "value"),
+			slog.Group("group", slog.Group("nested", slog.Int("key", 123))),
+		},
+	}
+
+	testCases := map[string]struct {
+		path      string
+		wantValue slog.Value
+		wantOK    bool
+	}{
+		"returns a root attr":                       {path: "key", wantValue: slog.StringValue("value"), wantOK: true},
+		"returns a nested attr":                     {path: "group.nested.key", wantValue: slog.IntValue(123), wantOK: true},
+		"returns a group attr":                      {path: "group.nested", wantValue: slog.GroupValue(slog.Int("key", 123)), wantOK: true},
+		"returns false for a missing attr":          {path: "missing", wantValue: slog.Value{}, wantOK: false},
+		"returns false for a missing nested attr":   {path: "group.missing", wantValue: slog.Value{}, wantOK: false},
+		"returns false when traversing a non group": {path: "key.nested", wantValue: slog.Value{}, wantOK: false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			gotValue, gotOK := record.Attr(tc.path)
+			if gotOK != tc.wantOK || !gotValue.Equal(tc.wantValue) {
+				t.Errorf("record.Attr(%q) want: (%v, %t), got: (%v, %t)", tc.path, tc.wantValue, tc.wantOK, gotValue, gotOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package slogmem
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// UpdateGoldenEnvVar is the name of the environment variable that causes golden
+// files to be rewritten rather than compared when set to a true value, for
+// example: `SLOGMEM_UPDATE_GOLDEN=1 go test ./...`.
+const UpdateGoldenEnvVar = "SLOGMEM_UPDATE_GOLDEN"
+
+// maskedValue replaces the value of any attribute masked via [WithMaskedAttrs].
+const maskedValue = "[MASKED]"
+
+type (
+	// GoldenOption is an optional configuration value used to configure
+	// [LoggedRecords.AssertGolden].
+	GoldenOption func(*goldenOptions)
+
+	goldenOptions struct {
+		path           string
+		maskedPaths    [][]string
+		normalizeTimes bool
+		update         bool
+	}
+)
+
+// WithGoldenFile sets the path of the golden file. The default is
+// testdata/<test name>.golden relative to the package under test.
+func WithGoldenFile(path string) GoldenOption {
+	return func(o *goldenOptions) {
+		o.path = path
+	}
+}
+
+// WithMaskedAttrs replaces the values of the attributes at the given dot
+// separated paths with a fixed placeholder. This is useful for volatile values
+// such as IDs or durations that change between test runs.
+func WithMaskedAttrs(paths ...string) GoldenOption {
+	return func(o *goldenOptions) {
+		for _, path := range paths {
+			o.maskedPaths = append(o.maskedPaths, strings.Split(path, "."))
+		}
+	}
+}
+
+// WithNormalizedTimes keeps the time of each record in the golden file as the
+// duration elapsed since the first record with a time was logged. The time is
+// stripped from records without one. The default is to strip the time from
+// each record.
+func WithNormalizedTimes() GoldenOption {
+	return func(o *goldenOptions) {
+		o.normalizeTimes = true
+	}
+}
+
+// WithGoldenUpdate sets whether the golden file is written with the current
+// records rather than compared to them. This allows a test package to wire up
+// its own -update flag for example. The default is taken from the
+// [UpdateGoldenEnvVar] environment variable.
+func WithGoldenUpdate(update bool) GoldenOption {
+	return func(o *goldenOptions) {
+		o.update = update
+	}
+}
+
+// AssertGolden serializes the LoggedRecords and compares them to the contents
+// of a golden file, reporting an error on tb when they differ. When
+// [WithGoldenUpdate] is set, or the [UpdateGoldenEnvVar] environment variable
+// is true, the golden file is written with the current records instead.
+//
+// There is no -update flag as registering one from a library package would
+// clash with any flag of the same name in the package under test. A test
+// package that prefers a flag can declare its own and pass it on:
+//
+//	var update = flag.Bool("update", false, "rewrite golden files")
+//
+//	records.AssertGolden(t, slogmem.WithGoldenUpdate(*update))
+//
+// Records are serialized as indented JSON with keys in a stable order so that
+// the golden file can be reviewed in diffs.
+func (lr *LoggedRecords) AssertGolden(tb testing.TB, options ...GoldenOption) {
+	tb.Helper()
+
+	opts := goldenOptions{
+		path:           filepath.Join("testdata", goldenFileName(tb.Name())),
+		maskedPaths:    nil,
+		normalizeTimes: false,
+		update:         updateGoldenFromEnv(),
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	got, err := lr.marshalGolden(opts)
+	if err != nil {
+		tb.Fatalf("marshalling records for golden file %s: %v", opts.path, err)
+		return
+	}
+
+	if opts.update {
+		if err := writeGoldenFile(opts.path, got); err != nil {
+			tb.Fatalf("writing golden file %s: %v", opts.path, err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(opts.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			tb.Fatalf("golden file %s does not exist, run the test with %s=1 to create it", opts.path, UpdateGoldenEnvVar)
+			return
+		}
+
+		tb.Fatalf("reading golden file %s: %v", opts.path, err)
+
+		return
+	}
+
+	if !bytes.Equal(want, got) {
+		tb.Errorf("logged records do not match golden file %s (-want +got):\n%s", opts.path, cmp.Diff(string(want), string(got)))
+	}
+}
+
+// marshalGolden serializes the records as JSON with the time stripped or
+// normalized and any masked attributes replaced.
+func (lr *LoggedRecords) marshalGolden(opts goldenOptions) ([]byte, error) {
+	records := lr.snapshot()
+	flattenedRecords := flattenRecords(records)
+	start := firstRecordTime(records)
+
+	for i, flattenedRecord := range flattenedRecords {
+		if opts.normalizeTimes && !records[i].Time.IsZero() {
+			flattenedRecord[slog.TimeKey] = "+" + records[i].Time.Sub(start).String()
+		} else {
+			delete(flattenedRecord, slog.TimeKey)
+		}
+
+		for _, path := range opts.maskedPaths {
+			maskPath(flattenedRecord, path)
+		}
+
+		flattenedRecords[i] = goldenValues(flattenedRecord)
+	}
+
+	b, err := json.MarshalIndent(flattenedRecords, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshalling json: %w", err)
+	}
+
+	return append(b, '\n'), nil
+}
+
+// firstRecordTime returns the time of the first record that has one, which is
+// the time that normalized times are relative to.
+func firstRecordTime(records []LoggedRecord) time.Time {
+	for _, record := range records {
+		if !record.Time.IsZero() {
+			return record.Time
+		}
+	}
+
+	return time.Time{}
+}
+
+// maskPath replaces the value at the given path in the record, if it exists.
+func maskPath(record map[string]any, path []string) {
+	value, ok := record[path[0]]
+	if !ok {
+		return
+	}
+
+	if len(path) == 1 {
+		record[path[0]] = maskedValue
+		return
+	}
+
+	if group, ok := value.(map[string]any); ok {
+		maskPath(group, path[1:])
+	}
+}
+
+// goldenValues converts values in the record that do not serialize well as
+// JSON into their string representations.
+func goldenValues(record map[string]any) map[string]any {
+	for key, value := range record {
+		switch v := value.(type) {
+		case map[string]any:
+			record[key] = goldenValues(v)
+		case time.Time:
+			record[key] = v.Format(time.RFC3339Nano)
+		case error:
+			record[key] = v.Error()
+		case fmt.Stringer:
+			record[key] = v.String()
+		}
+	}
+
+	return record
+}
+
+// updateGoldenFromEnv reports whether the [UpdateGoldenEnvVar] environment
+// variable has been set to a true value.
+func updateGoldenFromEnv() bool {
+	update, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnvVar))
+	return update
+}
+
+// writeGoldenFile writes the golden file, creating any missing directories.
+func writeGoldenFile(path string, data []byte) error {
+	const dirPerm, filePerm = 0o755, 0o644
+
+	if err := os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, filePerm); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+
+	return nil
+}
+
+// goldenFileName converts a test name into a file name that is safe to use on
+// all platforms.
+func goldenFileName(testName string) string {
+	return strings.NewReplacer("/", string(filepath.Separator), " ", "_", ":", "_").Replace(testName) + ".golden"
+}
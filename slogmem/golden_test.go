@@ -0,0 +1,148 @@
+package slogmem_test
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+// recordingTB captures failures reported by [slogmem.LoggedRecords.AssertGolden]
+// so that failing comparisons can be asserted on.
+type recordingTB struct {
+	testing.TB
+
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestLoggedRecordsAssertGolden(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 5, 28, 1, 0, 0, 0, time.UTC)
+
+	records := slogmem.NewLoggedRecords([]slogmem.LoggedRecord{
+		{
+			Time:    start,
+			Level:   slog.LevelInfo,
+			Message: "request started",
+			Attrs: []slog.Attr{
+				slog.String("request_id", "a1b2c3"),
+				slog.Group("http", slog.String("method", "GET"), slog.String("path", "/users")),
+			},
+		},
+		{
+			Time:    start.Add(150 * time.Millisecond),
+			Level:   slog.LevelError,
+			Message: "request failed",
+			Attrs: []slog.Attr{
+				slog.String("request_id", "a1b2c3"),
+				slog.Any("error", errors.New("connection refused")),
+				slog.Group("http", slog.Int("status", 500), slog.Duration("duration", 150*time.Millisecond)),
+			},
+		},
+	})
+
+	t.Run("matches the golden file with masked attrs and times stripped", func(t *testing.T) {
+		t.Parallel()
+
+		records.AssertGolden(t, slogmem.WithMaskedAttrs("request_id", "http.duration"))
+	})
+
+	t.Run("matches the golden file with normalized times", func(t *testing.T) {
+		t.Parallel()
+
+		records.AssertGolden(t, slogmem.WithNormalizedTimes())
+	})
+
+	t.Run("normalizes times relative to the first record with a time", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "zero_time.golden")
+		want := `[
+  {
+    "level": "INFO",
+    "msg": "without a time"
+  },
+  {
+    "level": "INFO",
+    "msg": "first with a time",
+    "time": "+0s"
+  },
+  {
+    "level": "INFO",
+    "msg": "second with a time",
+    "time": "+1s"
+  }
+]
+`
+
+		if err := os.WriteFile(path, []byte(want), 0o600); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+
+		slogmem.NewLoggedRecords([]slogmem.LoggedRecord{
+			{Time: time.Time{}, Level: slog.LevelInfo, Message: "without a time"},
+			{Time: start, Level: slog.LevelInfo, Message: "first with a time"},
+			{Time: start.Add(time.Second), Level: slog.LevelInfo, Message: "second with a time"},
+		}).AssertGolden(t, slogmem.WithGoldenFile(path), slogmem.WithNormalizedTimes(), slogmem.WithGoldenUpdate(false))
+	})
+
+	t.Run("reports a diff when the records do not match the golden file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "mismatch.golden")
+		if err := os.WriteFile(path, []byte("[]\n"), 0o600); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+
+		tb := &recordingTB{TB: t, failures: nil}
+
+		records.AssertGolden(tb, slogmem.WithGoldenFile(path), slogmem.WithGoldenUpdate(false))
+
+		if len(tb.failures) != 1 || !strings.Contains(tb.failures[0], "do not match golden file") {
+			t.Errorf("AssertGolden() failures want: a single golden file mismatch, got: %v", tb.failures)
+		}
+	})
+
+	t.Run("writes the golden file when updating", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "nested", "updated.golden")
+
+		records.AssertGolden(t, slogmem.WithGoldenFile(path), slogmem.WithGoldenUpdate(true))
+
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("os.Stat(%q) returned error: %v", path, err)
+		}
+
+		records.AssertGolden(t, slogmem.WithGoldenFile(path), slogmem.WithGoldenUpdate(false))
+	})
+
+	t.Run("reports a missing golden file", func(t *testing.T) {
+		t.Parallel()
+
+		tb := &recordingTB{TB: t, failures: nil}
+
+		records.AssertGolden(tb, slogmem.WithGoldenFile(filepath.Join(t.TempDir(), "does_not_exist.golden")), slogmem.WithGoldenUpdate(false))
+
+		if len(tb.failures) != 1 || !strings.Contains(tb.failures[0], "does not exist") {
+			t.Errorf("AssertGolden() failures want: a single missing golden file failure, got: %v", tb.failures)
+		}
+	})
+}
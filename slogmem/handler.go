@@ -4,6 +4,9 @@ package slogmem
 import (
 	"context"
 	"log/slog"
+	"runtime"
+	"slices"
+	"time"
 
 	"github.com/nickbryan/slogutil/internal"
 )
@@ -14,6 +17,7 @@ type Handler struct {
 	persistentAttrs internal.AttrGroupTree
 	leveler         slog.Leveler
 	loggedRecords   *LoggedRecords
+	opts            options
 }
 
 // Ensure that our [Handler] implements the [slog.Handler] interface.
@@ -21,11 +25,14 @@ var _ slog.Handler = &Handler{} //nolint:exhaustruct // Compile type implementat
 
 // NewHandler creates a new in-memory Handler that captures log records which have a
 // level greater than or equal to the current level of the given leveler.
-func NewHandler(leveler slog.Leveler) *Handler {
+func NewHandler(leveler slog.Leveler, options ...Option) *Handler {
+	opts := mapOptionsToDefaults(options)
+
 	return &Handler{
 		persistentAttrs: internal.NewAttrGroupTree(),
 		leveler:         leveler,
-		loggedRecords:   NewLoggedRecords(make([]LoggedRecord, 0)),
+		loggedRecords:   newBoundedLoggedRecords(make([]LoggedRecord, 0), opts.capacity, opts.maxAge),
+		opts:            opts,
 	}
 }
 
@@ -36,6 +43,7 @@ func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 		persistentAttrs: h.persistentAttrs.WithAttrs(attrs),
 		leveler:         h.leveler,
 		loggedRecords:   h.loggedRecords,
+		opts:            h.opts,
 	}
 }
 
@@ -46,6 +54,7 @@ func (h *Handler) WithGroup(name string) slog.Handler {
 		persistentAttrs: h.persistentAttrs.WithGroup(name),
 		leveler:         h.leveler,
 		loggedRecords:   h.loggedRecords,
+		opts:            h.opts,
 	}
 }
 
@@ -65,19 +74,128 @@ func (h *Handler) Records() *LoggedRecords {
 // [LoggedRecords] store.
 //
 // Handle will only be called when [Enabled] returns true.
-func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+func (h *Handler) Handle(ctx context.Context, record slog.Record) error {
 	recordAttrs := make([]slog.Attr, 0, record.NumAttrs())
 	record.Attrs(func(attr slog.Attr) bool {
 		recordAttrs = append(recordAttrs, attr)
 		return true
 	})
 
-	h.loggedRecords.append(LoggedRecord{
+	history := h.persistentAttrs.WithAttrs(recordAttrs).History()
+	resolveAttrs := history.DeduplicatedAttrs
+
+	if h.opts.mergeGroups {
+		resolveAttrs = history.MergedAttrs
+	}
+
+	loggedRecord := LoggedRecord{
 		Time:    record.Time,
 		Level:   record.Level,
 		Message: record.Message,
-		Attrs:   h.persistentAttrs.WithAttrs(recordAttrs).History().DeduplicatedAttrs(),
-	})
+		Attrs:   resolveAttrs(),
+		PC:      record.PC,
+		Source:  nil,
+		Context: h.captureContext(ctx),
+	}
+
+	if h.opts.addSource {
+		loggedRecord.Source = source(record.PC)
+	}
+
+	if h.opts.replaceAttr != nil {
+		loggedRecord = h.replaceAttrs(loggedRecord)
+	}
+
+	h.loggedRecords.append(loggedRecord)
 
 	return nil
 }
+
+// replaceAttrs applies the configured [ReplaceAttrFunc] to the built-in and
+// user attributes of the record. Removing the built-in time or source clears it
+// from the record. Every record has a level and message, so removing either of
+// them is ignored, as are replacements of a different kind.
+func (h *Handler) replaceAttrs(record LoggedRecord) LoggedRecord {
+	if !record.Time.IsZero() {
+		attr := h.opts.replaceAttr(nil, slog.Time(slog.TimeKey, record.Time))
+		if attr.Equal(slog.Attr{}) {
+			record.Time = time.Time{}
+		} else if attr.Value.Kind() == slog.KindTime {
+			record.Time = attr.Value.Time()
+		}
+	}
+
+	if attr := h.opts.replaceAttr(nil, slog.Any(slog.LevelKey, record.Level)); !attr.Equal(slog.Attr{}) {
+		if level, ok := attr.Value.Any().(slog.Level); ok {
+			record.Level = level
+		}
+	}
+
+	if attr := h.opts.replaceAttr(nil, slog.String(slog.MessageKey, record.Message)); attr.Value.Kind() == slog.KindString {
+		record.Message = attr.Value.String()
+	}
+
+	if record.Source != nil {
+		attr := h.opts.replaceAttr(nil, slog.Any(slog.SourceKey, record.Source))
+		if attr.Equal(slog.Attr{}) {
+			record.Source = nil
+		} else if src, ok := attr.Value.Any().(*slog.Source); ok {
+			record.Source = src
+		}
+	}
+
+	record.Attrs = h.replaceGroupAttrs(nil, record.Attrs)
+
+	return record
+}
+
+// replaceGroupAttrs recursively applies the configured [ReplaceAttrFunc] to the
+// attrs within the given groups, removing any attrs that are replaced with an
+// empty [slog.Attr] and any groups that become empty as a result.
+func (h *Handler) replaceGroupAttrs(groups []string, attrs []slog.Attr) []slog.Attr {
+	replaced := make([]slog.Attr, 0, len(attrs))
+
+	for _, attr := range attrs {
+		if attr.Value.Kind() == slog.KindGroup {
+			groupAttrs := h.replaceGroupAttrs(append(slices.Clip(groups), attr.Key), attr.Value.Group())
+			if len(groupAttrs) > 0 {
+				replaced = append(replaced, slog.Attr{Key: attr.Key, Value: slog.GroupValue(groupAttrs...)})
+			}
+
+			continue
+		}
+
+		if attr = h.opts.replaceAttr(groups, attr); !attr.Equal(slog.Attr{}) {
+			attr.Value = attr.Value.Resolve()
+			replaced = append(replaced, attr)
+		}
+	}
+
+	return replaced
+}
+
+// captureContext returns the values selected from ctx by the configured
+// [ContextCaptureFunc], or nil when one has not been configured.
+func (h *Handler) captureContext(ctx context.Context) map[string]any {
+	if h.opts.captureContext == nil || ctx == nil {
+		return nil
+	}
+
+	return h.opts.captureContext(ctx)
+}
+
+// source resolves the function, file and line of the given program counter.
+// A nil [slog.Source] is returned when the program counter is zero.
+func source(pc uintptr) *slog.Source {
+	if pc == 0 {
+		return nil
+	}
+
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+
+	return &slog.Source{
+		Function: frame.Function,
+		File:     frame.File,
+		Line:     frame.Line,
+	}
+}
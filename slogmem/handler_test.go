@@ -1,6 +1,7 @@
 package slogmem_test
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"maps"
@@ -27,36 +28,267 @@ import (
 func TestHandlerSatisfiesSlogTestHarness(t *testing.T) {
 	t.Parallel()
 
-	handler := slogmem.NewHandler(slog.LevelDebug)
+	for name, options := range map[string][]slogmem.Option{
+		"Default":      nil,
+		"MergedGroups": {slogmem.WithMergedGroups(true)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var handler *slogmem.Handler
+
+			newHandler := func(*testing.T) slog.Handler {
+				handler = slogmem.NewHandler(slog.LevelDebug, options...)
+				return handler
+			}
+
+			result := func(t *testing.T) map[string]any {
+				t.Helper()
+
+				records := handler.Records().AsSliceOfNestedKeyValuePairs()
+
+				for _, record := range records {
+					// Unexpected key "time": a Handler should ignore a zero Record.Time
+					//
+					// The testing/slogtest harness executes the above assertion. We want to ensure
+					// that we capture zero time for debugging purposes when the in memory Handler is
+					// used for such cases. We capture all time values in the Handler and we delete
+					// them here in order to past the test harness as per https://pkg.go.dev/testing/slogtest#TestHandler.
+					maps.DeleteFunc(record, func(key string, value any) bool {
+						if t, ok := value.(time.Time); ok && key == slog.TimeKey {
+							return t.IsZero() // Delete time attribute where value is zero.
+						}
 
-	results := func() []map[string]any {
-		records := handler.Records().AsSliceOfNestedKeyValuePairs()
-
-		for _, record := range records {
-			// Unexpected key "time": a Handler should ignore a zero Record.Time
-			//
-			// The testing/slogtest harness executes the above assertion. We want to ensure
-			// that we capture zero time for debugging purposes when the in memory Handler is
-			// used for such cases. We capture all time values in the Handler and we delete
-			// them here in order to past the test harness as per https://pkg.go.dev/testing/slogtest#TestHandler.
-			maps.DeleteFunc(record, func(key string, value any) bool {
-				if t, ok := value.(time.Time); ok && key == slog.TimeKey {
-					return t.IsZero() // Delete time attribute where value is zero.
+						return false
+					})
 				}
 
-				return false
-			})
+				if len(records) != 1 {
+					jsonResults, err := json.MarshalIndent(records, "", "  ")
+					if err != nil {
+						t.Fatalf("Unable to marshal JSON results: got: %v, want: no marshal errors", err)
+					}
+
+					t.Fatalf("expected a single record to be captured, got: \n%s", jsonResults)
+				}
+
+				return records[0]
+			}
+
+			slogtest.Run(t, newHandler, result)
+		})
+	}
+}
+
+func TestHandlerCapturesZeroTime(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+
+	if err := handler.Handle(context.Background(), slog.NewRecord(time.Time{}, slog.LevelInfo, "Some message", 0)); err != nil {
+		t.Fatalf("handler.Handle() returned unexpected error: %v", err)
+	}
+
+	for record := range handler.Records().All() {
+		if !record.Time.IsZero() {
+			t.Errorf("record.Time want: zero time, got: %s", record.Time)
+		}
+	}
+
+	for _, record := range handler.Records().AsSliceOfNestedKeyValuePairs() {
+		if got, ok := record[slog.TimeKey].(time.Time); !ok || !got.IsZero() {
+			t.Errorf("AsSliceOfNestedKeyValuePairs() does not contain the zero time, got: %+v", record)
 		}
+	}
+}
 
-		return records
+type ctxKeyTenant struct{}
+
+func TestHandlerCapturesSource(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	logger := slog.New(handler)
+
+	logger.InfoContext(context.Background(), "Some message")
+
+	testCases := map[string]struct {
+		query slogmem.RecordQuery
+		want  bool
+	}{
+		"matches on the function that logged the record": {
+			query: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Some message",
+				Source:  &slog.Source{Function: "github.com/nickbryan/slogutil/slogmem_test.TestHandlerCapturesSource", File: "", Line: 0},
+			},
+			want: true,
+		},
+		"does not match on a different function": {
+			query: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Some message",
+				Source:  &slog.Source{Function: "github.com/nickbryan/slogutil/slogmem_test.SomeOtherFunction", File: "", Line: 0},
+			},
+			want: false,
+		},
+		"does not match on a different line": {
+			query: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "Some message",
+				Source:  &slog.Source{Function: "", File: "", Line: 1},
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, diff := handler.Records().ContainsExact(tc.query); got != tc.want {
+				t.Errorf("handler.Records().ContainsExact(%+v) want: %t, got: %t, diff: %s", tc.query, tc.want, got, diff)
+			}
+		})
+	}
+}
+
+func TestHandlerCapturesContextValues(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug, slogmem.WithContextCapture(func(ctx context.Context) map[string]any {
+		return map[string]any{"tenant": ctx.Value(ctxKeyTenant{})}
+	}))
+	logger := slog.New(handler)
+
+	logger.InfoContext(context.WithValue(context.Background(), ctxKeyTenant{}, "acme"), "Some message")
+
+	query := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "Some message",
+		Context: map[string]any{"tenant": "acme"},
+	}
+
+	if ok, diff := handler.Records().ContainsExact(query); !ok {
+		t.Errorf("handler.Records().ContainsExact(%+v) returned false, diff: %s", query, diff)
 	}
 
-	if err := slogtest.TestHandler(handler, results); err != nil {
-		jsonResults, marshalErr := json.MarshalIndent(results(), "", "  ")
-		if marshalErr != nil {
-			t.Fatalf("Unable to marshal JSON results: got: %v, want: no marshal errors", marshalErr)
+	query.Context = map[string]any{"tenant": "globex"}
+
+	if ok, _ := handler.Records().ContainsExact(query); ok {
+		t.Errorf("handler.Records().ContainsExact(%+v) returned true for a different context value", query)
+	}
+}
+
+func TestHandlerWithCapacityKeepsOnlyTheMostRecentRecords(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug, slogmem.WithCapacity(2))
+	logger := slog.New(handler)
+
+	mark := handler.Records().Mark()
+
+	logger.InfoContext(context.Background(), "first")
+	logger.InfoContext(context.Background(), "second")
+	logger.InfoContext(context.Background(), "third")
+
+	records := handler.Records()
+
+	if got := records.Len(); got != 2 {
+		t.Errorf("records.Len() want: 2, got: %d", got)
+	}
+
+	if got := records.Evicted(); got != 1 {
+		t.Errorf("records.Evicted() want: 1, got: %d", got)
+	}
+
+	if ok, _ := records.Contains(slogmem.RecordQuery{Level: slog.LevelInfo, Message: "first"}); ok {
+		t.Errorf("records contain the evicted record")
+	}
+
+	for _, msg := range []string{"second", "third"} {
+		if ok, diff := records.Contains(slogmem.RecordQuery{Level: slog.LevelInfo, Message: msg}); !ok {
+			t.Errorf("records do not contain %q, diff: %s", msg, diff)
+		}
+	}
+
+	if got := records.Since(mark).Len(); got != 2 {
+		t.Errorf("records.Since(mark).Len() want: 2, got: %d", got)
+	}
+}
+
+func TestHandlerWithMaxAgeEvictsExpiredRecords(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug, slogmem.WithMaxAge(time.Minute))
+
+	for _, record := range []slog.Record{
+		slog.NewRecord(time.Now().Add(-time.Hour), slog.LevelInfo, "expired", 0),
+		slog.NewRecord(time.Now(), slog.LevelInfo, "recent", 0),
+	} {
+		if err := handler.Handle(context.Background(), record); err != nil {
+			t.Fatalf("handler.Handle() returned unexpected error: %v", err)
 		}
+	}
+
+	records := handler.Records()
+
+	if got := records.Len(); got != 1 {
+		t.Errorf("records.Len() want: 1, got: %d", got)
+	}
+
+	if got := records.Evicted(); got != 1 {
+		t.Errorf("records.Evicted() want: 1, got: %d", got)
+	}
+
+	if ok, diff := records.Contains(slogmem.RecordQuery{Level: slog.LevelInfo, Message: "recent"}); !ok {
+		t.Errorf("records do not contain the recent record, diff: %s", diff)
+	}
+}
+
+func TestHandlerWithMaxAgeMeasuresAgeFromTheNewestRecord(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug, slogmem.WithMaxAge(time.Minute))
+	start := time.Date(2024, 5, 28, 1, 0, 0, 0, time.UTC)
+
+	for _, record := range []slog.Record{
+		slog.NewRecord(start, slog.LevelInfo, "first", 0),
+		slog.NewRecord(start, slog.LevelInfo, "second", 0),
+	} {
+		if err := handler.Handle(context.Background(), record); err != nil {
+			t.Fatalf("handler.Handle() returned unexpected error: %v", err)
+		}
+	}
+
+	if got := handler.Records().Len(); got != 2 {
+		t.Errorf("records.Len() with a past record time want: 2, got: %d", got)
+	}
+
+	if err := handler.Handle(context.Background(), slog.NewRecord(start.Add(time.Hour), slog.LevelInfo, "third", 0)); err != nil {
+		t.Fatalf("handler.Handle() returned unexpected error: %v", err)
+	}
+
+	if got := handler.Records().Evicted(); got != 2 {
+		t.Errorf("records.Evicted() want: 2, got: %d", got)
+	}
+}
+
+func TestHandlerWithMergedGroupsMergesGroupsWithTheSameName(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug, slogmem.WithMergedGroups(true))
+	logger := slog.New(handler)
+
+	logger.With(slog.Group("http", slog.String("method", "GET"))).WithGroup("http").InfoContext(context.Background(), "Some message", slog.Int("status", 200))
+
+	query := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "Some message",
+		Attrs:   map[string]slog.Value{"http.method": slog.StringValue("GET"), "http.status": slog.IntValue(200)},
+	}
 
-		t.Errorf("testing/slogtest harness is not satisfied for slogmem.Handler\ngot error: \n%s\n\ngot logs: \n%s", err, jsonResults)
+	if ok, diff := handler.Records().ContainsExact(query); !ok {
+		t.Errorf("handler.Records().ContainsExact(%+v) returned false, diff: %s", query, diff)
 	}
 }
@@ -0,0 +1,299 @@
+package slogmem
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// debugPage is the template that renders the captured records as an HTML table
+// with a form for filtering them.
+const debugPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Recent logs</title>
+<style>
+body { font-family: monospace; margin: 1em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; vertical-align: top; }
+</style>
+</head>
+<body>
+<h1>Recent logs</h1>
+<form method="get">
+<label>Level <input name="level" value="{{.Query.Get "level"}}" placeholder="INFO"></label>
+<label>Message <input name="msg" value="{{.Query.Get "msg"}}"></label>
+<label>Attr <input name="attr" value="{{.Query.Get "attr"}}" placeholder="group.key=value"></label>
+<button type="submit">Filter</button>
+</form>
+<p>Showing {{len .Records}} records, {{.Evicted}} evicted.</p>
+<table>
+<thead><tr><th>Time</th><th>Level</th><th>Message</th><th>Attributes</th></tr></thead>
+<tbody>
+{{- range .Records}}
+<tr><td>{{.Time.Format "2006-01-02T15:04:05.000Z07:00"}}</td><td>{{.Level}}</td><td>{{.Message}}</td><td>{{range flattenAttrs .Attrs}}{{.}}<br>{{end}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+</body>
+</html>
+`
+
+// recordFilter selects the records served by the handler returned from
+// [NewHTTPHandler] based on the request's query parameters.
+type recordFilter struct {
+	minLevel *slog.Level
+	message  string
+	attrs    []attrFilter
+}
+
+// attrFilter matches a record that has an attribute at the given path and,
+// when hasValue is set, that the attribute's value is equal to value.
+type attrFilter struct {
+	path     string
+	value    string
+	hasValue bool
+}
+
+// NewHTTPHandler creates an [http.Handler] that serves the given
+// [LoggedRecords] for inspection. This is intended to be used with a
+// [Handler] configured with [WithCapacity] or [WithMaxAge] in order to expose
+// the recent logs of a long-running process on a debug page.
+//
+// The following routes are served relative to where the handler is mounted,
+// use [http.StripPrefix] when mounting it under a path:
+//
+//   - GET / renders the records as an HTML page.
+//   - GET /json writes the records as JSON lines (see [LoggedRecords.WriteJSON]).
+//   - GET /tail streams records as they are captured as server-sent events.
+//
+// Each route accepts the following query parameters to filter the records:
+//
+//   - level: the minimum [slog.Level] of the records, for example: WARN.
+//   - msg: a substring that the record's message must contain.
+//   - attr: a dot separated attribute path that the record must contain,
+//     optionally followed by =value to match the attribute's value. This may be
+//     repeated to match multiple attributes.
+func NewHTTPHandler(records *LoggedRecords) http.Handler {
+	page := template.Must(template.New("debug").Funcs(template.FuncMap{"flattenAttrs": flattenAttrs}).Parse(debugPage))
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		serveRecords(w, r, records, func(w http.ResponseWriter, query url.Values, all, filtered *LoggedRecords) error {
+			return writeHTMLRecords(w, page, query, all, filtered)
+		})
+	})
+	mux.HandleFunc("GET /json", func(w http.ResponseWriter, r *http.Request) {
+		serveRecords(w, r, records, writeJSONRecords)
+	})
+	mux.HandleFunc("GET /tail", func(w http.ResponseWriter, r *http.Request) {
+		tailRecords(w, r, records)
+	})
+
+	return mux
+}
+
+// serveRecords filters the records with the request's query parameters and
+// writes them to the response with the given writer.
+func serveRecords(
+	w http.ResponseWriter,
+	r *http.Request,
+	records *LoggedRecords,
+	write func(w http.ResponseWriter, query url.Values, all, filtered *LoggedRecords) error,
+) {
+	filter, err := parseRecordFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := write(w, r.URL.Query(), records, records.Where(filter.matches)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeHTMLRecords renders the filtered records as an HTML page with the given
+// template.
+func writeHTMLRecords(w http.ResponseWriter, page *template.Template, query url.Values, all, filtered *LoggedRecords) error {
+	var buf bytes.Buffer
+
+	err := page.Execute(&buf, map[string]any{
+		"Query":   query,
+		"Records": filtered.snapshot(),
+		"Evicted": all.Evicted(),
+	})
+	if err != nil {
+		return fmt.Errorf("rendering template: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = buf.WriteTo(w)
+
+	return nil
+}
+
+// writeJSONRecords writes the filtered records as JSON lines.
+func writeJSONRecords(w http.ResponseWriter, _ url.Values, _, filtered *LoggedRecords) error {
+	var buf bytes.Buffer
+
+	if err := filtered.WriteJSON(&buf); err != nil {
+		return fmt.Errorf("writing json: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	_, _ = buf.WriteTo(w)
+
+	return nil
+}
+
+// tailRecords streams records that match the request's query parameters as
+// server-sent events until the request's context is done.
+func tailRecords(w http.ResponseWriter, r *http.Request, records *LoggedRecords) {
+	const keepAliveInterval = 15 * time.Second
+
+	filter, err := parseRecordFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The mark is taken before the headers are flushed so that any record
+	// captured after the client has connected will be streamed.
+	mark := records.Mark()
+	controller := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if err := controller.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		var (
+			newRecords []LoggedRecord
+			changed    <-chan struct{}
+		)
+
+		newRecords, mark, changed = records.since(mark)
+
+		if err := writeEvents(w, newRecords, filter); err != nil {
+			return
+		}
+
+		if err := controller.Flush(); err != nil {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-changed:
+		}
+	}
+}
+
+// writeEvents writes each of the records that match the filter as a
+// server-sent event with the record as JSON data.
+func writeEvents(w http.ResponseWriter, records []LoggedRecord, filter recordFilter) error {
+	for _, record := range records {
+		if !filter.matches(record) {
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := NewLoggedRecords([]LoggedRecord{record}).WriteJSON(&buf); err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", bytes.TrimSpace(buf.Bytes())); err != nil {
+			return fmt.Errorf("writing event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// parseRecordFilter creates a recordFilter from the given query parameters.
+func parseRecordFilter(query url.Values) (recordFilter, error) {
+	filter := recordFilter{
+		minLevel: nil,
+		message:  query.Get("msg"),
+		attrs:    make([]attrFilter, 0, len(query["attr"])),
+	}
+
+	if level := query.Get("level"); level != "" {
+		var minLevel slog.Level
+		if err := minLevel.UnmarshalText([]byte(level)); err != nil {
+			return recordFilter{}, fmt.Errorf("invalid level %q: %w", level, err)
+		}
+
+		filter.minLevel = &minLevel
+	}
+
+	for _, attr := range query["attr"] {
+		if attr == "" {
+			continue
+		}
+
+		path, value, hasValue := strings.Cut(attr, "=")
+		filter.attrs = append(filter.attrs, attrFilter{path: path, value: value, hasValue: hasValue})
+	}
+
+	return filter, nil
+}
+
+// matches reports whether the record satisfies the filter.
+func (f recordFilter) matches(record LoggedRecord) bool {
+	if f.minLevel != nil && record.Level < *f.minLevel {
+		return false
+	}
+
+	if !strings.Contains(record.Message, f.message) {
+		return false
+	}
+
+	for _, attr := range f.attrs {
+		value, ok := record.Attr(attr.path)
+		if !ok || (attr.hasValue && value.Resolve().String() != attr.value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// flattenAttrs formats the attrs as a list of dot separated key=value pairs.
+func flattenAttrs(attrs []slog.Attr) []string {
+	var flattened []string
+
+	var walk func(prefix string, attrs []slog.Attr)
+	walk = func(prefix string, attrs []slog.Attr) {
+		for _, attr := range attrs {
+			if attr.Value.Kind() == slog.KindGroup {
+				walk(prefix+attr.Key+".", attr.Value.Group())
+				continue
+			}
+
+			flattened = append(flattened, prefix+attr.Key+"="+attr.Value.Resolve().String())
+		}
+	}
+
+	walk("", attrs)
+
+	return flattened
+}
@@ -0,0 +1,175 @@
+package slogmem_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func newHTTPTestServer(t *testing.T) (*httptest.Server, *slog.Logger) {
+	t.Helper()
+
+	handler := slogmem.NewHandler(slog.LevelDebug, slogmem.WithCapacity(3))
+	logger := slog.New(handler)
+
+	logger.InfoContext(context.Background(), "evicted record")
+	logger.DebugContext(context.Background(), "cache miss", slog.String("tenant", "acme"))
+	logger.InfoContext(context.Background(), "request finished", slog.String("tenant", "acme"), slog.Group("http", slog.Int("status", 200)))
+	logger.ErrorContext(context.Background(), "request failed", slog.String("tenant", "globex"), slog.Group("http", slog.Int("status", 500)))
+
+	server := httptest.NewServer(slogmem.NewHTTPHandler(handler.Records()))
+	t.Cleanup(server.Close)
+
+	return server, logger
+}
+
+func TestHTTPHandlerServesFilteredRecords(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newHTTPTestServer(t)
+
+	testCases := map[string]struct {
+		path            string
+		wantStatus      int
+		wantContentType string
+		wantContains    []string
+		wantNotContains []string
+	}{
+		"renders all records as html": {
+			path:            "/",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/html; charset=utf-8",
+			wantContains:    []string{"cache miss", "request finished", "request failed", "http.status=500", "1 evicted"},
+			wantNotContains: []string{"evicted record"},
+		},
+		"filters html records by level": {
+			path:            "/?level=WARN",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/html; charset=utf-8",
+			wantContains:    []string{"request failed"},
+			wantNotContains: []string{"cache miss", "request finished"},
+		},
+		"writes all records as json": {
+			path:            "/json",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantContains:    []string{`"msg":"cache miss"`, `"msg":"request finished"`, `"msg":"request failed"`},
+		},
+		"filters json records by message substring": {
+			path:            "/json?msg=request",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantContains:    []string{`"msg":"request finished"`, `"msg":"request failed"`},
+			wantNotContains: []string{`"msg":"cache miss"`},
+		},
+		"filters json records by attr path and value": {
+			path:            "/json?attr=tenant=acme&attr=http.status",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/x-ndjson",
+			wantContains:    []string{`"msg":"request finished"`},
+			wantNotContains: []string{`"msg":"cache miss"`, `"msg":"request failed"`},
+		},
+		"returns bad request for an invalid level": {
+			path:            "/json?level=LOUD",
+			wantStatus:      http.StatusBadRequest,
+			wantContentType: "text/plain; charset=utf-8",
+			wantContains:    []string{"invalid level"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+tc.path, nil)
+			if err != nil {
+				t.Fatalf("creating request: %v", err)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("sending request: %v", err)
+			}
+			defer res.Body.Close()
+
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatalf("reading body: %v", err)
+			}
+
+			if res.StatusCode != tc.wantStatus {
+				t.Errorf("GET %s status want: %d, got: %d", tc.path, tc.wantStatus, res.StatusCode)
+			}
+
+			if got := res.Header.Get("Content-Type"); got != tc.wantContentType {
+				t.Errorf("GET %s Content-Type want: %q, got: %q", tc.path, tc.wantContentType, got)
+			}
+
+			for _, want := range tc.wantContains {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("GET %s body does not contain %q, got: %s", tc.path, want, body)
+				}
+			}
+
+			for _, notWant := range tc.wantNotContains {
+				if strings.Contains(string(body), notWant) {
+					t.Errorf("GET %s body contains %q, got: %s", tc.path, notWant, body)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPHandlerTailsNewRecordsAsServerSentEvents(t *testing.T) {
+	t.Parallel()
+
+	server, logger := newHTTPTestServer(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/tail?level=ERROR", nil)
+	if err != nil {
+		t.Fatalf("creating request: %v", err)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("sending request: %v", err)
+	}
+	defer res.Body.Close()
+
+	if got := res.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("GET /tail Content-Type want: %q, got: %q", "text/event-stream", got)
+	}
+
+	logger.InfoContext(context.Background(), "filtered out")
+	logger.ErrorContext(context.Background(), "tailed record", slog.String("tenant", "acme"))
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		if strings.Contains(line, "filtered out") || strings.Contains(line, "request failed") {
+			t.Fatalf("GET /tail streamed a record that should not have been sent: %s", line)
+		}
+
+		if !strings.Contains(line, `"msg":"tailed record"`) || !strings.Contains(line, `"tenant":"acme"`) {
+			t.Fatalf("GET /tail streamed unexpected record: %s", line)
+		}
+
+		return
+	}
+
+	t.Fatalf("GET /tail stream ended without the tailed record: %v", scanner.Err())
+}
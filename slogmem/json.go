@@ -0,0 +1,290 @@
+package slogmem
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+)
+
+// maxJSONLineSize is the maximum size of a single log line accepted by [ParseJSON].
+const maxJSONLineSize = 1024 * 1024
+
+// ParseJSON reads JSON log lines, such as those written by a [slog.JSONHandler],
+// from r and returns them as [LoggedRecords] so that they can be queried in the
+// same way as records captured by a [Handler]. Empty lines are skipped.
+//
+// The time, level, msg and source keys are mapped to the corresponding fields of
+// each [LoggedRecord]. All other keys are mapped to attributes in the order
+// that they appear, with nested objects mapped to groups.
+func ParseJSON(r io.Reader) (*LoggedRecords, error) {
+	records := make([]LoggedRecord, 0)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxJSONLineSize)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		record, err := parseJSONRecord(line)
+		if err != nil {
+			return nil, fmt.Errorf("parsing line %d: %w", lineNumber, err)
+		}
+
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading lines: %w", err)
+	}
+
+	return NewLoggedRecords(records), nil
+}
+
+// WriteJSON writes each of the LoggedRecords to w as a line of JSON in the same
+// format as a [slog.JSONHandler]. The output can be read back with [ParseJSON].
+func (lr *LoggedRecords) WriteJSON(w io.Writer) error {
+	for record := range lr.All() {
+		var buf bytes.Buffer
+
+		if !record.Time.IsZero() {
+			writeJSONField(&buf, slog.TimeKey, slog.TimeValue(record.Time))
+		}
+
+		writeJSONField(&buf, slog.LevelKey, slog.StringValue(record.Level.String()))
+
+		if record.Source != nil {
+			writeJSONField(&buf, slog.SourceKey, slog.GroupValue(
+				slog.String("function", record.Source.Function),
+				slog.String("file", record.Source.File),
+				slog.Int("line", record.Source.Line),
+			))
+		}
+
+		writeJSONField(&buf, slog.MessageKey, slog.StringValue(record.Message))
+
+		for _, attr := range record.Attrs {
+			writeJSONField(&buf, attr.Key, attr.Value)
+		}
+
+		if _, err := fmt.Fprintf(w, "{%s}\n", buf.Bytes()); err != nil {
+			return fmt.Errorf("writing record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// parseJSONRecord parses a single JSON object into a [LoggedRecord].
+func parseJSONRecord(line []byte) (LoggedRecord, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+
+	attrs, err := decodeJSONObject(decoder)
+	if err != nil {
+		return LoggedRecord{}, err
+	}
+
+	record := LoggedRecord{
+		Time:    time.Time{},
+		Level:   slog.LevelInfo,
+		Message: "",
+		Attrs:   make([]slog.Attr, 0, len(attrs)),
+		PC:      0,
+		Source:  nil,
+		Context: nil,
+	}
+
+	for _, attr := range attrs {
+		switch attr.Key {
+		case slog.TimeKey:
+			if record.Time, err = time.Parse(time.RFC3339Nano, attr.Value.String()); err != nil {
+				return LoggedRecord{}, fmt.Errorf("parsing time: %w", err)
+			}
+		case slog.LevelKey:
+			if err := record.Level.UnmarshalText([]byte(attr.Value.String())); err != nil {
+				return LoggedRecord{}, fmt.Errorf("parsing level: %w", err)
+			}
+		case slog.MessageKey:
+			record.Message = attr.Value.String()
+		case slog.SourceKey:
+			record.Source = parseJSONSource(attr.Value)
+		default:
+			record.Attrs = append(record.Attrs, attr)
+		}
+	}
+
+	return record, nil
+}
+
+// parseJSONSource maps a decoded source group onto a [slog.Source].
+func parseJSONSource(value slog.Value) *slog.Source {
+	source := &slog.Source{Function: "", File: "", Line: 0}
+
+	for _, attr := range value.Group() {
+		switch attr.Key {
+		case "function":
+			source.Function = attr.Value.String()
+		case "file":
+			source.File = attr.Value.String()
+		case "line":
+			source.Line = int(attr.Value.Int64())
+		}
+	}
+
+	return source
+}
+
+// decodeJSONObject decodes the next JSON object from the decoder as a slice of
+// [slog.Attr], preserving the order of the keys.
+func decodeJSONObject(decoder *json.Decoder) ([]slog.Attr, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, fmt.Errorf("reading object start: %w", err)
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("expected object, got: %v", token)
+	}
+
+	attrs := make([]slog.Attr, 0)
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("reading object key: %w", err)
+		}
+
+		key, ok := token.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected object key, got: %v", token)
+		}
+
+		value, err := decodeJSONValue(decoder)
+		if err != nil {
+			return nil, fmt.Errorf("reading value of %q: %w", key, err)
+		}
+
+		attrs = append(attrs, slog.Attr{Key: key, Value: value})
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("reading object end: %w", err)
+	}
+
+	return attrs, nil
+}
+
+// decodeJSONValue decodes the next JSON value from the decoder as a
+// [slog.Value]. Objects are decoded as groups and integers are decoded as
+// int64 values where possible so that they match queries using [slog.IntValue].
+func decodeJSONValue(decoder *json.Decoder) (slog.Value, error) {
+	if !decoder.More() {
+		return slog.Value{}, errors.New("unexpected end of input")
+	}
+
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return slog.Value{}, fmt.Errorf("decoding value: %w", err)
+	}
+
+	if len(raw) > 0 && raw[0] == '{' {
+		nested := json.NewDecoder(bytes.NewReader(raw))
+		nested.UseNumber()
+
+		attrs, err := decodeJSONObject(nested)
+		if err != nil {
+			return slog.Value{}, err
+		}
+
+		return slog.GroupValue(attrs...), nil
+	}
+
+	var value any
+
+	nested := json.NewDecoder(bytes.NewReader(raw))
+	nested.UseNumber()
+
+	if err := nested.Decode(&value); err != nil {
+		return slog.Value{}, fmt.Errorf("decoding value: %w", err)
+	}
+
+	if number, ok := value.(json.Number); ok {
+		if i, err := number.Int64(); err == nil {
+			return slog.Int64Value(i), nil
+		}
+
+		f, err := number.Float64()
+		if err != nil {
+			return slog.Value{}, fmt.Errorf("decoding number: %w", err)
+		}
+
+		return slog.Float64Value(f), nil
+	}
+
+	return slog.AnyValue(value), nil
+}
+
+// writeJSONField writes a comma separated "key":value pair to buf.
+func writeJSONField(buf *bytes.Buffer, key string, value slog.Value) {
+	if buf.Len() > 0 {
+		buf.WriteByte(',')
+	}
+
+	writeJSONString(buf, key)
+	buf.WriteByte(':')
+	writeJSONValue(buf, value.Resolve())
+}
+
+// writeJSONValue writes value to buf in the same format as a [slog.JSONHandler].
+func writeJSONValue(buf *bytes.Buffer, value slog.Value) {
+	switch value.Kind() {
+	case slog.KindGroup:
+		var group bytes.Buffer
+
+		for _, attr := range value.Group() {
+			writeJSONField(&group, attr.Key, attr.Value)
+		}
+
+		fmt.Fprintf(buf, "{%s}", group.Bytes())
+	case slog.KindTime:
+		writeJSONString(buf, value.Time().Format(time.RFC3339Nano))
+	case slog.KindDuration:
+		fmt.Fprintf(buf, "%d", value.Duration().Nanoseconds())
+	case slog.KindString:
+		writeJSONString(buf, value.String())
+	case slog.KindInt64, slog.KindUint64, slog.KindFloat64, slog.KindBool:
+		writeJSONAny(buf, value.Any())
+	case slog.KindAny, slog.KindLogValuer:
+		if err, ok := value.Any().(error); ok {
+			writeJSONString(buf, err.Error())
+			return
+		}
+
+		writeJSONAny(buf, value.Any())
+	}
+}
+
+// writeJSONAny marshals v as JSON, writing an error string in its place when
+// it cannot be marshalled as is done by [slog.JSONHandler].
+func writeJSONAny(buf *bytes.Buffer, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		writeJSONString(buf, "!ERROR:"+err.Error())
+		return
+	}
+
+	buf.Write(b)
+}
+
+// writeJSONString writes s to buf as a quoted JSON string.
+func writeJSONString(buf *bytes.Buffer, s string) {
+	writeJSONAny(buf, s)
+}
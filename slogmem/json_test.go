@@ -0,0 +1,145 @@
+package slogmem_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nickbryan/slogutil"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestParseJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	logger := slogutil.NewJSONLogger(slogutil.WithWriter(&buf), slogutil.WithLevel(slog.LevelDebug))
+	logger = logger.With(slog.String("service", "api")).WithGroup("http")
+
+	logger.DebugContext(context.Background(), "request started", slog.String("method", "GET"))
+	logger.ErrorContext(context.Background(), "request failed", slog.Int("status", 500), slog.Any("error", errors.New("connection refused")))
+
+	records, err := slogmem.ParseJSON(&buf)
+	if err != nil {
+		t.Fatalf("slogmem.ParseJSON() returned unexpected error: %v", err)
+	}
+
+	if got := records.Len(); got != 2 {
+		t.Fatalf("records.Len() want: 2, got: %d", got)
+	}
+
+	testCases := map[string]slogmem.RecordQuery{
+		"matches the attrs of a record": {
+			Level:   slog.LevelDebug,
+			Message: "request started",
+			Attrs:   map[string]slog.Value{"service": slog.StringValue("api"), "http.method": slog.StringValue("GET")},
+		},
+		"matches integer and error attrs of a record": {
+			Level:   slog.LevelError,
+			Message: "request failed",
+			Attrs: map[string]slog.Value{
+				"service":     slog.StringValue("api"),
+				"http.status": slog.IntValue(500),
+				"http.error":  slog.AnyValue(errors.New("connection refused")),
+			},
+		},
+		"matches the source of a record": {
+			Level:   slog.LevelError,
+			Message: "request failed",
+			Source:  &slog.Source{Function: "github.com/nickbryan/slogutil/slogmem_test.TestParseJSON"},
+		},
+	}
+
+	for name, query := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if ok, diff := records.Contains(query); !ok {
+				t.Errorf("records.Contains(%+v) returned false, diff: %s", query, diff)
+			}
+		})
+	}
+}
+
+func TestParseJSONReturnsAnErrorForInvalidLines(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		input   string
+		wantErr string
+	}{
+		"invalid json": {
+			input:   `{"level":"INFO","msg":"ok"}` + "\n" + `{"level":`,
+			wantErr: "parsing line 2",
+		},
+		"not an object": {
+			input:   `["level","INFO"]`,
+			wantErr: "expected object",
+		},
+		"invalid level": {
+			input:   `{"level":"LOUD","msg":"ok"}`,
+			wantErr: "parsing level",
+		},
+		"invalid time": {
+			input:   `{"time":"yesterday","level":"INFO","msg":"ok"}`,
+			wantErr: "parsing time",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := slogmem.ParseJSON(strings.NewReader(tc.input))
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("slogmem.ParseJSON(%q) error want: containing %q, got: %v", tc.input, tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoggedRecordsWriteJSONCanBeParsed(t *testing.T) {
+	t.Parallel()
+
+	fixedNow := time.Date(2024, 5, 28, 1, 0, 0, 0, time.UTC)
+
+	records := slogmem.NewLoggedRecords([]slogmem.LoggedRecord{
+		{
+			Time:    fixedNow,
+			Level:   slog.LevelWarn,
+			Message: "some message",
+			Attrs: []slog.Attr{
+				slog.String("key", "value"),
+				slog.Group("group", slog.Int("int", 123), slog.Bool("bool", true), slog.Float64("float", 1.5)),
+			},
+			Source: &slog.Source{Function: "main.main", File: "main.go", Line: 10},
+		},
+	})
+
+	var buf bytes.Buffer
+	if err := records.WriteJSON(&buf); err != nil {
+		t.Fatalf("records.WriteJSON() returned unexpected error: %v", err)
+	}
+
+	wantJSON := `{"time":"2024-05-28T01:00:00Z","level":"WARN","source":{"function":"main.main","file":"main.go","line":10},"msg":"some message","key":"value","group":{"int":123,"bool":true,"float":1.5}}` + "\n"
+	if got := buf.String(); got != wantJSON {
+		t.Errorf("records.WriteJSON() want: %s, got: %s", wantJSON, got)
+	}
+
+	parsed, err := slogmem.ParseJSON(&buf)
+	if err != nil {
+		t.Fatalf("slogmem.ParseJSON() returned unexpected error: %v", err)
+	}
+
+	want, got := records.AsSliceOfNestedKeyValuePairs(), parsed.AsSliceOfNestedKeyValuePairs()
+	if !cmp.Equal(want, got) {
+		t.Errorf("parsed records do not match written records, diff: %s", cmp.Diff(want, got))
+	}
+}
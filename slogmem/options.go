@@ -0,0 +1,107 @@
+package slogmem
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type (
+	// Option is an optional configuration value used to configure a [Handler].
+	Option func(*options)
+
+	options struct {
+		captureContext ContextCaptureFunc
+		capacity       int
+		maxAge         time.Duration
+		addSource      bool
+		replaceAttr    ReplaceAttrFunc
+		mergeGroups    bool
+	}
+)
+
+// ReplaceAttrFunc is called to rewrite each attribute before it is captured.
+// It behaves in the same way as [slog.HandlerOptions.ReplaceAttr].
+type ReplaceAttrFunc func(groups []string, attr slog.Attr) slog.Attr
+
+// ContextCaptureFunc represents a function that knows how to select values from
+// the [context.Context] passed to [Handler.Handle] so that they can be retained
+// on the [LoggedRecord] for inspection.
+type ContextCaptureFunc func(ctx context.Context) map[string]any
+
+// WithContextCapture sets the [ContextCaptureFunc] that will be called for each
+// handled record. The returned values are stored in [LoggedRecord.Context]. The
+// default is to not capture any context values.
+func WithContextCapture(capture ContextCaptureFunc) Option {
+	return func(o *options) {
+		o.captureContext = capture
+	}
+}
+
+// WithCapacity bounds the [LoggedRecords] of the [Handler] to the given number
+// of records. Once the capacity is reached, the oldest record is evicted each
+// time a new record is captured. The default is zero, which is unbounded.
+func WithCapacity(capacity int) Option {
+	return func(o *options) {
+		o.capacity = capacity
+	}
+}
+
+// WithMaxAge bounds the [LoggedRecords] of the [Handler] to records that were
+// logged within the given duration of the newest captured record. Older
+// records are evicted as new records are captured. Ages are measured between
+// record times rather than against the wall clock so that records logged with
+// a fixed or past time are retained. The default is zero, which is unbounded.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(o *options) {
+		o.maxAge = maxAge
+	}
+}
+
+// WithSourceAdded sets whether the [LoggedRecord.Source] is resolved for each
+// captured record. The default is true.
+func WithSourceAdded(addSource bool) Option {
+	return func(o *options) {
+		o.addSource = addSource
+	}
+}
+
+// WithReplaceAttr sets the [ReplaceAttrFunc] that will be used to rewrite
+// attributes before they are captured, mirroring
+// [slog.HandlerOptions.ReplaceAttr]. It is called for each non-group attribute
+// with the list of groups that contain it, and for the built-in time, level,
+// message and source attributes with nil groups. Returning an empty
+// [slog.Attr] removes the attribute, except for the built-in level and message
+// which every record has. The default is nil.
+func WithReplaceAttr(replaceAttr ReplaceAttrFunc) Option {
+	return func(o *options) {
+		o.replaceAttr = replaceAttr
+	}
+}
+
+// WithMergedGroups sets whether groups with the same key at the same group level
+// are merged into a single group rather than the keys of the later groups being
+// suffixed with #0x. Duplicate keys within the merged groups are still
+// suffixed. The default is false.
+func WithMergedGroups(mergeGroups bool) Option {
+	return func(o *options) {
+		o.mergeGroups = mergeGroups
+	}
+}
+
+func mapOptionsToDefaults(opts []Option) options {
+	mappedDefaultOpts := options{
+		captureContext: nil,
+		capacity:       0,
+		maxAge:         0,
+		addSource:      true,
+		replaceAttr:    nil,
+		mergeGroups:    false,
+	}
+
+	for _, opt := range opts {
+		opt(&mappedDefaultOpts)
+	}
+
+	return mappedDefaultOpts
+}
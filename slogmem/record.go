@@ -1,12 +1,15 @@
 package slogmem
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"maps"
+	"math"
 	"slices"
 	"strings"
 	"sync"
+	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -26,15 +29,41 @@ type (
 		// Attrs is a slice of [slog.Attr] records that represent the additional
 		// attributes that were added to the log entry by the caller as context.
 		Attrs []slog.Attr
+		// PC is the program counter of the call that wrote the log entry. It is
+		// zero when the [slog.Record] was created without a program counter or
+		// when the record was not captured by a [Handler].
+		PC uintptr
+		// Source is the location of the call that wrote the log entry. It is nil
+		// when the [slog.Record] was created without a program counter.
+		Source *slog.Source
+		// Context holds the values captured from the [context.Context] the log
+		// entry was written with. See [WithContextCapture].
+		Context map[string]any
 	}
 
 	// LoggedRecords is a slice of [LoggedRecord] entries that were captured by a [Handler].
-	// Adding to LoggedRecords is safe to do concurrently.
+	// Adding to and reading from LoggedRecords is safe to do concurrently. Each
+	// read operates on a snapshot of the records taken at the time of the call.
+	//
+	// When the [Handler] is configured with [WithCapacity] or [WithMaxAge], the
+	// oldest records are evicted to keep the LoggedRecords within those bounds.
 	LoggedRecords struct {
-		mu      sync.Mutex
-		records []LoggedRecord
+		mu          sync.RWMutex
+		records     []LoggedRecord
+		offset      int
+		subscribers map[*LoggedRecords]struct{}
+		capacity    int
+		maxAge      time.Duration
+		evicted     int
+		newest      time.Time
+		changed     chan struct{}
 	}
 
+	// Mark is a checkpoint within a set of [LoggedRecords] that can be passed to
+	// [LoggedRecords.Since] in order to query only the records that were captured
+	// after the checkpoint was taken.
+	Mark int
+
 	// RecordQuery represents the relevant information required in order to query for
 	// the existence of a [LoggedRecord] within a set of [LoggedRecords]. Time is not
 	// part of the query as it is generally difficult to know when the log was
@@ -49,16 +78,55 @@ type (
 		// written as `slog.Group("group", slog.String("key", "value"))` then to query
 		// that, we would pass `map[string]slog.Value{"group.key": slog.StringValue("value")}`.
 		Attrs map[string]slog.Value
+		// Source, when set, is matched against the [LoggedRecord.Source]. Only the
+		// non-zero fields of Source are compared, allowing a query to match on the
+		// function alone for example.
+		Source *slog.Source
+		// Context, when set, is matched against the [LoggedRecord.Context]. Only
+		// the keys present in Context are compared.
+		Context map[string]any
 	}
 )
 
+// String returns the LoggedRecord in the format of a [slog.TextHandler] so that
+// records remain readable when they are printed in test failures.
+func (r LoggedRecord) String() string {
+	var sb strings.Builder
+
+	handler := slog.NewTextHandler(&sb, &slog.HandlerOptions{AddSource: false, Level: slog.Level(math.MinInt), ReplaceAttr: nil})
+
+	record := slog.NewRecord(r.Time, r.Level, r.Message, 0)
+	if r.Source != nil {
+		record.AddAttrs(slog.String(slog.SourceKey, fmt.Sprintf("%s:%d", r.Source.File, r.Source.Line)))
+	}
+
+	record.AddAttrs(r.Attrs...)
+
+	_ = handler.Handle(context.Background(), record) // Writing to a strings.Builder does not fail.
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
 // NewLoggedRecords encapsulates the given list of [LoggedRecord] entries within
 // a LoggedRecords struct to represent the list of logged records in a way that
 // is easy to lookup when asserting logs in tests or similar.
 func NewLoggedRecords(records []LoggedRecord) *LoggedRecords {
+	return newBoundedLoggedRecords(records, 0, 0)
+}
+
+// newBoundedLoggedRecords creates a LoggedRecords that will hold at most capacity
+// records that are no older than maxAge. A zero value disables the bound.
+func newBoundedLoggedRecords(records []LoggedRecord, capacity int, maxAge time.Duration) *LoggedRecords {
 	return &LoggedRecords{
-		mu:      sync.Mutex{},
-		records: records,
+		mu:          sync.RWMutex{},
+		records:     records,
+		offset:      0,
+		subscribers: make(map[*LoggedRecords]struct{}),
+		capacity:    capacity,
+		maxAge:      maxAge,
+		evicted:     0,
+		newest:      time.Time{},
+		changed:     nil,
 	}
 }
 
@@ -82,8 +150,7 @@ func NewLoggedRecords(records []LoggedRecord) *LoggedRecords {
 // convenience helper for logging information in failed tests and similar
 // scenarios.
 func (lr *LoggedRecords) Contains(query RecordQuery) (ok bool, diff string) {
-	paths := append(slices.Collect(maps.Keys(query.Attrs)), slog.MessageKey, slog.LevelKey)
-	return lr.compare(query, append(cmpOpts(), includePaths(paths))...)
+	return lr.compare(query, containsOpts(query)...)
 }
 
 // ContainsExact can be used to check if a LoggedRecords contains a
@@ -113,7 +180,20 @@ func (lr *LoggedRecords) ContainsExact(query RecordQuery) (ok bool, diff string)
 func (lr *LoggedRecords) IsEmpty() bool { return lr.Len() == 0 }
 
 // Len returns the number of records that have been captured.
-func (lr *LoggedRecords) Len() int { return len(lr.records) }
+func (lr *LoggedRecords) Len() int {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	return len(lr.records)
+}
+
+// Snapshot returns a copy of the LoggedRecords as they are at the time of the
+// call. Records captured by the [Handler] after Snapshot returns will not be
+// added to the copy, making it safe to inspect while logging continues in the
+// background.
+func (lr *LoggedRecords) Snapshot() *LoggedRecords {
+	return NewLoggedRecords(lr.snapshot())
+}
 
 // AsSliceOfNestedKeyValuePairs flattens the LoggedRecords so that they can be
 // accessed as a series of key value pair objects representing each recorded log.
@@ -121,11 +201,16 @@ func (lr *LoggedRecords) Len() int { return len(lr.records) }
 // This method would be used when formatting the recorded log records as JSON for
 // example.
 func (lr *LoggedRecords) AsSliceOfNestedKeyValuePairs() []map[string]any {
+	return flattenRecords(lr.snapshot())
+}
+
+// flattenRecords converts each [LoggedRecord] into a nested map of key value pairs.
+func flattenRecords(records []LoggedRecord) []map[string]any {
 	const numBaseAttrs = 3 // time, level, message
 
-	flattenedRecords := make([]map[string]any, 0, len(lr.records))
+	flattenedRecords := make([]map[string]any, 0, len(records))
 
-	for _, rec := range lr.records {
+	for _, rec := range records {
 		flattenedRecord := make(map[string]any, numBaseAttrs+len(rec.Attrs))
 
 		flattenedRecord[slog.TimeKey] = rec.Time
@@ -142,12 +227,187 @@ func (lr *LoggedRecords) AsSliceOfNestedKeyValuePairs() []map[string]any {
 	return flattenedRecords
 }
 
-// append safely appends a [LoggedRecord] to the list of LoggedRecords.
+// Reset removes all captured records. Any [Mark] taken before the call to
+// Reset remains valid and will only match records captured after the Reset.
+func (lr *LoggedRecords) Reset() {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	lr.offset += len(lr.records)
+	lr.records = nil
+}
+
+// Mark returns a checkpoint representing the current position in the
+// LoggedRecords. Pass the returned [Mark] to [LoggedRecords.Since] to query
+// the records captured after this call.
+func (lr *LoggedRecords) Mark() Mark {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	return Mark(lr.offset + len(lr.records))
+}
+
+// Since returns a snapshot of the records that were captured after the given
+// [Mark] was taken.
+func (lr *LoggedRecords) Since(mark Mark) *LoggedRecords {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	return NewLoggedRecords(cloneRecords(lr.records[lr.index(mark):]))
+}
+
+// since returns a copy of the records captured after the given [Mark] along
+// with a [Mark] for the end of the returned records and a channel that will be
+// closed when the next record is captured. The channel is only created when
+// since is called so that records can be captured without allocating one when
+// nothing is waiting for them.
+func (lr *LoggedRecords) since(mark Mark) ([]LoggedRecord, Mark, <-chan struct{}) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if lr.changed == nil {
+		lr.changed = make(chan struct{})
+	}
+
+	return cloneRecords(lr.records[lr.index(mark):]), Mark(lr.offset + len(lr.records)), lr.changed
+}
+
+// index returns the index within the records of the first record captured
+// after the given [Mark]. The caller must hold the lock.
+func (lr *LoggedRecords) index(mark Mark) int {
+	return min(max(int(mark)-lr.offset, 0), len(lr.records))
+}
+
+// Scoped returns a new LoggedRecords that captures every record added to lr
+// from the time of the call until tb and its subtests complete. This is useful
+// when a logger is shared between table-driven subtests, as records from
+// earlier cases will not pollute the assertions of later ones:
+//
+//	for name, tc := range testCases {
+//		t.Run(name, func(t *testing.T) {
+//			records := logs.Scoped(t)
+//			...
+//		})
+//	}
+//
+// Records logged by parallel tests sharing the same logger will still be
+// captured by each scope that is active at the time.
+func (lr *LoggedRecords) Scoped(tb testing.TB) *LoggedRecords {
+	tb.Helper()
+
+	scoped := NewLoggedRecords(nil)
+
+	lr.mu.Lock()
+	if lr.subscribers == nil {
+		lr.subscribers = make(map[*LoggedRecords]struct{})
+	}
+
+	lr.subscribers[scoped] = struct{}{}
+	lr.mu.Unlock()
+
+	tb.Cleanup(func() {
+		lr.mu.Lock()
+		defer lr.mu.Unlock()
+
+		delete(lr.subscribers, scoped)
+	})
+
+	return scoped
+}
+
+// append safely appends a [LoggedRecord] to the list of LoggedRecords and any
+// scoped LoggedRecords that are currently subscribed.
 func (lr *LoggedRecords) append(record LoggedRecord) {
 	lr.mu.Lock()
 	defer lr.mu.Unlock()
 
 	lr.records = append(lr.records, record)
+
+	if lr.capacity > 0 && len(lr.records) > lr.capacity {
+		lr.evict(len(lr.records) - lr.capacity)
+	}
+
+	if record.Time.After(lr.newest) {
+		lr.newest = record.Time
+	}
+
+	lr.evictOlderThan(lr.newest)
+
+	for subscriber := range lr.subscribers {
+		subscriber.append(record)
+	}
+
+	if lr.changed != nil {
+		close(lr.changed)
+		lr.changed = nil
+	}
+}
+
+// Evicted returns the number of records that have been evicted to keep the
+// LoggedRecords within the bounds set by [WithCapacity] and [WithMaxAge].
+func (lr *LoggedRecords) Evicted() int {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	return lr.evicted
+}
+
+// evictOlderThan evicts records logged before newest minus the configured max
+// age. Records without a time are never considered expired. The caller must
+// hold the write lock.
+func (lr *LoggedRecords) evictOlderThan(newest time.Time) {
+	if lr.maxAge <= 0 || newest.IsZero() {
+		return
+	}
+
+	cutoff := newest.Add(-lr.maxAge)
+
+	n := 0
+	for n < len(lr.records) && !lr.records[n].Time.IsZero() && lr.records[n].Time.Before(cutoff) {
+		n++
+	}
+
+	lr.evict(n)
+}
+
+// evict removes the n oldest records. The caller must hold the write lock.
+func (lr *LoggedRecords) evict(n int) {
+	if n <= 0 {
+		return
+	}
+
+	clear(lr.records[:n])
+
+	lr.records = lr.records[n:]
+	lr.offset += n
+	lr.evicted += n
+}
+
+// snapshot returns a copy of the currently captured records. The attrs of each
+// record are cloned so that the copy shares no mutable state with lr.
+func (lr *LoggedRecords) snapshot() []LoggedRecord {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	return cloneRecords(lr.records)
+}
+
+// cloneRecords returns a copy of the given records with their attrs cloned.
+func cloneRecords(records []LoggedRecord) []LoggedRecord {
+	cloned := make([]LoggedRecord, 0, len(records))
+	for _, rec := range records {
+		rec.Attrs = slices.Clone(rec.Attrs)
+		rec.Context = maps.Clone(rec.Context)
+
+		if rec.Source != nil {
+			src := *rec.Source
+			rec.Source = &src
+		}
+
+		cloned = append(cloned, rec)
+	}
+
+	return cloned
 }
 
 func (lr *LoggedRecords) compare(query RecordQuery, opts ...cmp.Option) (bool, string) {
@@ -157,15 +417,18 @@ func (lr *LoggedRecords) compare(query RecordQuery, opts ...cmp.Option) (bool, s
 	)
 
 	flattenedQuery := flattenRecordQuery(query)
+	records := lr.snapshot()
 
-	for i, flattenedRecord := range lr.AsSliceOfNestedKeyValuePairs() {
-		if cmp.Equal(flattenedQuery, flattenedRecord, opts...) {
+	for i, flattenedRecord := range flattenRecords(records) {
+		metadataDiff := compareMetadata(query, records[i])
+
+		if metadataDiff == "" && cmp.Equal(flattenedQuery, flattenedRecord, opts...) {
 			return true, ""
 		}
 
-		recordDiff := cmp.Diff(flattenedQuery, flattenedRecord, opts...)
+		recordDiff := metadataDiff + cmp.Diff(flattenedQuery, flattenedRecord, opts...)
 
-		if lr.records[i].Message == query.Message {
+		if records[i].Message == query.Message {
 			msgMatchDiff.WriteString(fmt.Sprintln(recordDiff))
 		}
 
@@ -179,6 +442,54 @@ func (lr *LoggedRecords) compare(query RecordQuery, opts ...cmp.Option) (bool, s
 	return false, diff.String()
 }
 
+// containsOpts returns the cmp options used to perform a loose match of the
+// query against a record, ignoring any attrs that are not part of the query.
+func containsOpts(query RecordQuery) []cmp.Option {
+	paths := append(slices.Collect(maps.Keys(query.Attrs)), slog.MessageKey, slog.LevelKey)
+	return append(cmpOpts(), includePaths(paths))
+}
+
+// compareMetadata returns a diff of the source and context of the query and
+// record, or an empty string if the record satisfies the query.
+func compareMetadata(query RecordQuery, record LoggedRecord) string {
+	var diff string
+
+	if query.Source != nil {
+		gotSource := slog.Source{Function: "", File: "", Line: 0}
+		if record.Source != nil {
+			gotSource = *record.Source
+		}
+
+		if query.Source.Function == "" {
+			gotSource.Function = ""
+		}
+
+		if query.Source.File == "" {
+			gotSource.File = ""
+		}
+
+		if query.Source.Line == 0 {
+			gotSource.Line = 0
+		}
+
+		diff += cmp.Diff(*query.Source, gotSource)
+	}
+
+	if query.Context != nil {
+		gotContext := make(map[string]any, len(query.Context))
+
+		for key := range query.Context {
+			if value, ok := record.Context[key]; ok {
+				gotContext[key] = value
+			}
+		}
+
+		diff += cmp.Diff(query.Context, gotContext, cmpOpts()...)
+	}
+
+	return diff
+}
+
 // includePaths returns a cmp.Option that will ignore any paths that do not match the given paths.
 func includePaths(paths []string) cmp.Option { //nolint:ireturn // We need to return a cmp.Option here which is an interface.
 	include := make([][]string, 0, len(paths))
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"sync"
 	"testing"
 	"time"
 
@@ -22,6 +23,43 @@ func (e *logValuerStubError) LogValue() slog.Value {
 	return slog.GroupValue(slog.Int("i", e.I), slog.String("s", e.S))
 }
 
+func TestLoggedRecordString(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		record slogmem.LoggedRecord
+		want   string
+	}{
+		"renders the record in the text format": {
+			record: slogmem.LoggedRecord{
+				Time:    time.Date(2024, 5, 28, 1, 0, 0, 0, time.UTC),
+				Level:   slog.LevelWarn,
+				Message: "some message",
+				Attrs:   []slog.Attr{slog.String("key", "value"), slog.Group("group", slog.Int("int", 123))},
+			},
+			want: `time=2024-05-28T01:00:00.000Z level=WARN msg="some message" key=value group.int=123`,
+		},
+		"omits the time when it is zero and renders the source": {
+			record: slogmem.LoggedRecord{
+				Level:   slog.LevelDebug - 4,
+				Message: "trace",
+				Source:  &slog.Source{Function: "main.main", File: "main.go", Line: 10},
+			},
+			want: `level=DEBUG-4 msg=trace source=main.go:10`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.record.String(); got != tc.want {
+				t.Errorf("LoggedRecord.String() want: %s, got: %s", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestLoggedRecordsAsSliceOfNestedKeyValuePairs(t *testing.T) {
 	t.Parallel()
 
@@ -706,3 +744,233 @@ func TestHandlerRespectsCastingLogValuerWhenTestingErrors(t *testing.T) {
 		t.Errorf("handler does not respect slog.LogValuer casting, diff:\n%s", diff)
 	}
 }
+
+func TestLoggedRecordsSnapshot(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	logger := slog.New(handler)
+
+	logger.InfoContext(context.Background(), "first message", slog.String("key", "value"))
+
+	snapshot := handler.Records().Snapshot()
+
+	logger.InfoContext(context.Background(), "second message")
+
+	if got := snapshot.Len(); got != 1 {
+		t.Errorf("handler.Records().Snapshot().Len() want: 1, got: %d", got)
+	}
+
+	if got := handler.Records().Len(); got != 2 {
+		t.Errorf("handler.Records().Len() want: 2, got: %d", got)
+	}
+
+	query := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "first message",
+		Attrs:   map[string]slog.Value{"key": slog.StringValue("value")},
+	}
+
+	if ok, diff := snapshot.ContainsExact(query); !ok {
+		t.Errorf("snapshot.ContainsExact(%+v) returned false, diff: %s", query, diff)
+	}
+
+	if ok, _ := snapshot.Contains(slogmem.RecordQuery{Level: slog.LevelInfo, Message: "second message", Attrs: nil}); ok {
+		t.Errorf("snapshot contains a record that was logged after the snapshot was taken")
+	}
+}
+
+func TestLoggedRecordsIsSafeToQueryWhileLoggingConcurrently(t *testing.T) {
+	t.Parallel()
+
+	const numWriters, numLogsPerWriter = 8, 50
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	logger := slog.New(handler)
+	records := handler.Records()
+
+	var wg sync.WaitGroup
+
+	for writer := range numWriters {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range numLogsPerWriter {
+				logger.InfoContext(context.Background(), "concurrent message", slog.Int("writer", writer), slog.Int("i", i))
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	query := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "concurrent message",
+		Attrs:   map[string]slog.Value{"writer": slog.IntValue(0), "i": slog.IntValue(0)},
+	}
+
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+
+		_ = records.Len()
+		_ = records.IsEmpty()
+		_ = records.AsSliceOfNestedKeyValuePairs()
+		_, _ = records.Contains(query)
+		_, _ = records.ContainsExact(query)
+		_ = records.Snapshot()
+	}
+
+	if got, want := records.Len(), numWriters*numLogsPerWriter; got != want {
+		t.Errorf("records.Len() want: %d, got: %d", want, got)
+	}
+
+	if ok, diff := records.ContainsExact(query); !ok {
+		t.Errorf("records.ContainsExact(%+v) returned false, diff: %s", query, diff)
+	}
+}
+
+func TestLoggedRecordsReset(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	logger := slog.New(handler)
+
+	logger.InfoContext(context.Background(), "before reset")
+	handler.Records().Reset()
+
+	if !handler.Records().IsEmpty() {
+		t.Fatalf("handler.Records().IsEmpty() returned false after Reset")
+	}
+
+	logger.InfoContext(context.Background(), "after reset")
+
+	if ok, _ := handler.Records().Contains(slogmem.RecordQuery{Level: slog.LevelInfo, Message: "before reset", Attrs: nil}); ok {
+		t.Errorf("handler.Records() contains a record that was logged before Reset")
+	}
+
+	if ok, diff := handler.Records().Contains(slogmem.RecordQuery{Level: slog.LevelInfo, Message: "after reset", Attrs: nil}); !ok {
+		t.Errorf("handler.Records() does not contain a record that was logged after Reset, diff: %s", diff)
+	}
+}
+
+func TestLoggedRecordsSince(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		log      func(logger *slog.Logger, records *slogmem.LoggedRecords) slogmem.Mark
+		wantMsgs []string
+	}{
+		"returns no records when nothing has been logged since the mark": {
+			log: func(logger *slog.Logger, records *slogmem.LoggedRecords) slogmem.Mark {
+				logger.InfoContext(context.Background(), "first")
+				return records.Mark()
+			},
+			wantMsgs: nil,
+		},
+		"returns only the records logged since the mark": {
+			log: func(logger *slog.Logger, records *slogmem.LoggedRecords) slogmem.Mark {
+				logger.InfoContext(context.Background(), "first")
+				mark := records.Mark()
+				logger.InfoContext(context.Background(), "second")
+				logger.InfoContext(context.Background(), "third")
+
+				return mark
+			},
+			wantMsgs: []string{"second", "third"},
+		},
+		"returns only the records logged since the mark when reset after the mark": {
+			log: func(logger *slog.Logger, records *slogmem.LoggedRecords) slogmem.Mark {
+				logger.InfoContext(context.Background(), "first")
+				mark := records.Mark()
+				logger.InfoContext(context.Background(), "second")
+				records.Reset()
+				logger.InfoContext(context.Background(), "third")
+
+				return mark
+			},
+			wantMsgs: []string{"third"},
+		},
+		"returns only the records logged since the mark when reset before the mark": {
+			log: func(logger *slog.Logger, records *slogmem.LoggedRecords) slogmem.Mark {
+				logger.InfoContext(context.Background(), "first")
+				records.Reset()
+				logger.InfoContext(context.Background(), "second")
+				mark := records.Mark()
+				logger.InfoContext(context.Background(), "third")
+
+				return mark
+			},
+			wantMsgs: []string{"third"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := slogmem.NewHandler(slog.LevelDebug)
+			mark := tc.log(slog.New(handler), handler.Records())
+
+			var gotMsgs []string
+			for _, record := range handler.Records().Since(mark).AsSliceOfNestedKeyValuePairs() {
+				gotMsgs = append(gotMsgs, record[slog.MessageKey].(string)) //nolint:forcetypeassert // Message is always a string.
+			}
+
+			if !cmp.Equal(tc.wantMsgs, gotMsgs) {
+				t.Errorf("handler.Records().Since(%d) messages: want %v, got %v", mark, tc.wantMsgs, gotMsgs)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest,tparallel // The subtests share a logger so that each scope can be asserted in turn.
+func TestLoggedRecordsScoped(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	logger := slog.New(handler)
+
+	logger.InfoContext(context.Background(), "before subtests")
+
+	for _, subtest := range []string{"first", "second"} {
+		t.Run(subtest, func(t *testing.T) {
+			records := handler.Records().Scoped(t)
+
+			logger.InfoContext(context.Background(), "in subtest", slog.String("subtest", subtest))
+
+			if got := records.Len(); got != 1 {
+				t.Errorf("records.Len() want: 1, got: %d", got)
+			}
+
+			query := slogmem.RecordQuery{Level: slog.LevelInfo, Message: "in subtest", Attrs: map[string]slog.Value{"subtest": slog.StringValue(subtest)}}
+			if ok, diff := records.ContainsExact(query); !ok {
+				t.Errorf("scoped records do not contain the record logged in the subtest, diff: %s", diff)
+			}
+		})
+	}
+
+	if got := handler.Records().Len(); got != 3 {
+		t.Errorf("handler.Records().Len() want: 3, got: %d", got)
+	}
+}
+
+func TestLoggedRecordsScopedOnZeroValue(t *testing.T) {
+	t.Parallel()
+
+	var records slogmem.LoggedRecords
+
+	if scoped := records.Scoped(t); !scoped.IsEmpty() {
+		t.Errorf("records.Scoped(t).IsEmpty() returned false for a zero value LoggedRecords")
+	}
+}
@@ -0,0 +1,32 @@
+package slogmem
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Replay reconstructs a [slog.Record] for each of the LoggedRecords and passes
+// them in order to the given [slog.Handler]. Records for which the handler is
+// not enabled are skipped, as they would be by a [slog.Logger].
+//
+// The time, level, message, program counter and attrs, including any nested
+// groups, are preserved. Records that were not captured by a [Handler], such as
+// those read with [ParseJSON], have no program counter so the handler will not
+// be able to resolve their source.
+func (lr *LoggedRecords) Replay(ctx context.Context, handler slog.Handler) error {
+	for record := range lr.All() {
+		if !handler.Enabled(ctx, record.Level) {
+			continue
+		}
+
+		slogRecord := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+		slogRecord.AddAttrs(record.Attrs...)
+
+		if err := handler.Handle(ctx, slogRecord); err != nil {
+			return fmt.Errorf("replaying record to handler: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package slogmem_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestLoggedRecordsReplay(t *testing.T) {
+	t.Parallel()
+
+	handler := slogmem.NewHandler(slog.LevelDebug)
+	logger := slog.New(handler).With(slog.String("service", "api")).WithGroup("http")
+
+	logger.DebugContext(context.Background(), "request started", slog.String("method", "GET"))
+	logger.ErrorContext(context.Background(), "request failed", slog.Int("status", 500))
+
+	t.Run("replays records into another handler in order", func(t *testing.T) {
+		t.Parallel()
+
+		replayHandler := slogmem.NewHandler(slog.LevelDebug)
+
+		if err := handler.Records().Replay(context.Background(), replayHandler); err != nil {
+			t.Fatalf("Replay() returned unexpected error: %v", err)
+		}
+
+		want, got := handler.Records().AsSliceOfNestedKeyValuePairs(), replayHandler.Records().AsSliceOfNestedKeyValuePairs()
+		if !cmp.Equal(want, got) {
+			t.Errorf("replayed records do not match captured records, diff: %s", cmp.Diff(want, got))
+		}
+
+		query := slogmem.RecordQuery{
+			Level:   slog.LevelError,
+			Message: "request failed",
+			Source:  &slog.Source{Function: "github.com/nickbryan/slogutil/slogmem_test.TestLoggedRecordsReplay"},
+		}
+
+		if ok, diff := replayHandler.Records().Contains(query); !ok {
+			t.Errorf("replayed records do not preserve the source, diff: %s", diff)
+		}
+	})
+
+	t.Run("replays records into a JSON handler skipping records that are not enabled", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		jsonHandler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
+			AddSource: false,
+			Level:     slog.LevelInfo,
+			ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+				if attr.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+
+				return attr
+			},
+		})
+
+		if err := handler.Records().Replay(context.Background(), jsonHandler); err != nil {
+			t.Fatalf("Replay() returned unexpected error: %v", err)
+		}
+
+		want := `{"level":"ERROR","msg":"request failed","service":"api","http":{"status":500}}` + "\n"
+		if got := buf.String(); got != want {
+			t.Errorf("replayed JSON want: %s, got: %s", want, got)
+		}
+	})
+
+	t.Run("returns an error when the handler errors", func(t *testing.T) {
+		t.Parallel()
+
+		records := slogmem.NewLoggedRecords([]slogmem.LoggedRecord{{Time: time.Now(), Level: slog.LevelInfo, Message: "some message"}})
+
+		err := records.Replay(context.Background(), erroringHandler{err: errors.New("some handler error")})
+		if err == nil || !strings.Contains(err.Error(), "some handler error") {
+			t.Errorf("Replay() error want: wrapping %q, got: %v", "some handler error", err)
+		}
+	})
+}
+
+type erroringHandler struct {
+	err error
+}
+
+func (e erroringHandler) Enabled(_ context.Context, _ slog.Level) bool  { return true }
+func (e erroringHandler) Handle(_ context.Context, _ slog.Record) error { return e.err }
+func (e erroringHandler) WithAttrs(_ []slog.Attr) slog.Handler          { panic("unimplemented") }
+func (e erroringHandler) WithGroup(_ string) slog.Handler               { panic("unimplemented") }
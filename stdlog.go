@@ -0,0 +1,214 @@
+package slogutil
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stdLogTimestamp matches the date and time prefix written by a [log.Logger]
+// with the [log.LstdFlags] or [log.Lmicroseconds] flags set.
+var stdLogTimestamp = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2} )?\d{2}:\d{2}:\d{2}(\.\d{6})? `)
+
+type (
+	// BridgeOption is an optional configuration value used to configure
+	// [NewStdLogger] and [NewStdLogWriter].
+	BridgeOption func(*bridgeOptions)
+
+	bridgeOptions struct {
+		level         slog.Level
+		parseKeyValue bool
+	}
+
+	// stdLogWriter is an [io.Writer] that converts each message written to it
+	// into a record for a [slog.Handler].
+	stdLogWriter struct {
+		handler        slog.Handler
+		opts           bridgeOptions
+		stripTimestamp bool
+	}
+)
+
+// WithBridgeLevel sets the level of the records created from each message. The
+// default is [slog.LevelInfo].
+func WithBridgeLevel(level slog.Level) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.level = level
+	}
+}
+
+// WithKeyValueParsing sets whether key=value pairs in each message are extracted
+// as string attrs and removed from the message. Values may be quoted with
+// double quotes to include spaces, for example: user="Jane Doe". The default is
+// false.
+func WithKeyValueParsing(parseKeyValue bool) BridgeOption {
+	return func(o *bridgeOptions) {
+		o.parseKeyValue = parseKeyValue
+	}
+}
+
+// NewStdLogger creates a [log.Logger] that writes each message as a record to the
+// [slog.Handler] of the given logger so that libraries using the log package
+// are written in the same format as the rest of the application. When logger
+// is nil, [slog.Default] is used.
+//
+// The log package does not pass a [context.Context], use a logger returned by
+// [slogctx.Logger] to include context attributes.
+func NewStdLogger(logger *slog.Logger, options ...BridgeOption) *log.Logger {
+	writer := newStdLogWriter(logger, options)
+
+	// The logger is created without flags, so a message that starts with a
+	// time is not a prefix to be removed.
+	writer.stripTimestamp = false
+
+	return log.New(writer, "", 0)
+}
+
+// NewStdLogWriter creates an [io.Writer] that writes each message as a record to
+// the [slog.Handler] of the given logger in the same way as [NewStdLogger]. It
+// can be passed to [log.SetOutput] to bridge the output of the log package's
+// standard logger. The date and time prefix written by the standard logger is
+// removed as each record has its own time, use log.SetFlags(0) to remove other
+// prefixes. When logger is nil, [slog.Default] is used.
+func NewStdLogWriter(logger *slog.Logger, options ...BridgeOption) io.Writer {
+	return newStdLogWriter(logger, options)
+}
+
+// newStdLogWriter creates a stdLogWriter that removes the date and time prefix.
+func newStdLogWriter(logger *slog.Logger, options []BridgeOption) *stdLogWriter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	opts := bridgeOptions{
+		level:         slog.LevelInfo,
+		parseKeyValue: false,
+	}
+
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	return &stdLogWriter{handler: logger.Handler(), opts: opts, stripTimestamp: true}
+}
+
+// Write converts p into a single record as a [log.Logger] writes each message
+// with a single call to Write. Only the date and time prefix and the trailing
+// newline are removed, so a message that spans multiple lines is kept whole. It
+// always reports that all of p was written unless the handler returns an error.
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	ctx := context.Background()
+	if !w.handler.Enabled(ctx, w.opts.level) {
+		return len(p), nil
+	}
+
+	message := strings.TrimSuffix(string(p), "\n")
+	if w.stripTimestamp {
+		message = stdLogTimestamp.ReplaceAllString(message, "")
+	}
+
+	var attrs []slog.Attr
+	if w.opts.parseKeyValue {
+		message, attrs = parseKeyValuePairs(message)
+	}
+
+	record := slog.NewRecord(time.Now(), w.opts.level, message, callerPC())
+	record.AddAttrs(attrs...)
+
+	if err := w.handler.Handle(ctx, record); err != nil {
+		return 0, fmt.Errorf("passing record to handler: %w", err)
+	}
+
+	return len(p), nil
+}
+
+// callerPC returns the program counter of the first caller outside of the log
+// package and this writer, which is the code that called the log package.
+func callerPC() uintptr {
+	const skip = 3 // runtime.Callers, callerPC and Write.
+
+	var callers [16]uintptr
+
+	for _, pc := range callers[:runtime.Callers(skip, callers[:])] {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		if !strings.HasPrefix(frame.Function, "log.") {
+			return pc
+		}
+	}
+
+	return 0
+}
+
+// parseKeyValuePairs removes the key=value pairs from the message, returning
+// the remaining words of the message and the pairs as string attrs.
+func parseKeyValuePairs(message string) (string, []slog.Attr) {
+	var (
+		words []string
+		attrs []slog.Attr
+	)
+
+	for rest := strings.TrimSpace(message); rest != ""; rest = strings.TrimLeft(rest, " ") {
+		token, remaining := nextToken(rest)
+		rest = remaining
+
+		key, value, ok := strings.Cut(token, "=")
+		if !ok || !isKey(key) {
+			words = append(words, token)
+			continue
+		}
+
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+
+		attrs = append(attrs, slog.String(key, value))
+	}
+
+	return strings.Join(words, " "), attrs
+}
+
+// nextToken returns the first space separated token of s, treating spaces
+// within double quotes as part of the token, and the remainder of s.
+func nextToken(s string) (string, string) {
+	inQuotes, escaped := false, false
+
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && inQuotes:
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ' ' && !inQuotes:
+			return s[:i], s[i:]
+		}
+	}
+
+	return s, ""
+}
+
+// isKey reports whether s can be used as the key of a key=value pair.
+func isKey(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for i, r := range s {
+		isLetter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		isDigitOrPunct := (r >= '0' && r <= '9') || r == '.' || r == '-'
+
+		if !isLetter && (i == 0 || !isDigitOrPunct) {
+			return false
+		}
+	}
+
+	return true
+}
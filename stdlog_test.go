@@ -0,0 +1,173 @@
+package slogutil_test
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nickbryan/slogutil"
+	"github.com/nickbryan/slogutil/slogctx"
+	"github.com/nickbryan/slogutil/slogmem"
+)
+
+func TestNewStdLogger(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		options []slogutil.BridgeOption
+		log     func(logger *log.Logger)
+		want    slogmem.RecordQuery
+	}{
+		"writes each message as a record at the info level": {
+			options: nil,
+			log: func(logger *log.Logger) {
+				logger.Printf("connected to %s", "db")
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "connected to db",
+				Attrs:   map[string]slog.Value{},
+			},
+		},
+		"writes each message as a record at the configured level": {
+			options: []slogutil.BridgeOption{slogutil.WithBridgeLevel(slog.LevelWarn)},
+			log: func(logger *log.Logger) {
+				logger.Print("retrying request")
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelWarn,
+				Message: "retrying request",
+				Attrs:   map[string]slog.Value{},
+			},
+		},
+		"extracts key value pairs when enabled": {
+			options: []slogutil.BridgeOption{slogutil.WithKeyValueParsing(true)},
+			log: func(logger *log.Logger) {
+				logger.Printf(`request failed status=%d user="Jane Doe" a=b=c 1x=y`, 500)
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "request failed 1x=y",
+				Attrs: map[string]slog.Value{
+					"status": slog.StringValue("500"),
+					"user":   slog.StringValue("Jane Doe"),
+					"a":      slog.StringValue("b=c"),
+				},
+			},
+		},
+		"keeps a time at the start of the message": {
+			options: nil,
+			log: func(logger *log.Logger) {
+				logger.Print("12:00:00 job started")
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "12:00:00 job started",
+				Attrs:   map[string]slog.Value{},
+			},
+		},
+		"does not extract key value pairs by default": {
+			options: nil,
+			log: func(logger *log.Logger) {
+				logger.Print("request failed status=500")
+			},
+			want: slogmem.RecordQuery{
+				Level:   slog.LevelInfo,
+				Message: "request failed status=500",
+				Attrs:   map[string]slog.Value{},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo)
+
+			tc.log(slogutil.NewStdLogger(logger, tc.options...))
+
+			if ok, diff := logs.ContainsExact(tc.want); !ok {
+				t.Errorf("expected logged records to contain: %+v, got: %s", tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestNewStdLoggerIncludesContextAttrsAndSource(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo)
+	ctx := slogctx.WithLogger(slogctx.WithAttrs(context.Background(), slog.String("request_id", "abc")), logger)
+
+	slogutil.NewStdLogger(slogctx.Logger(ctx)).Print("Some message")
+
+	query := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "Some message",
+		Attrs:   map[string]slog.Value{"request_id": slog.StringValue("abc")},
+		Source:  &slog.Source{Function: "github.com/nickbryan/slogutil_test.TestNewStdLoggerIncludesContextAttrsAndSource"},
+	}
+
+	if ok, diff := logs.ContainsExact(query); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+	}
+}
+
+func TestNewStdLogWriter(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := slogutil.NewInMemoryLogger(slog.LevelWarn)
+
+	stdLogger := log.New(slogutil.NewStdLogWriter(logger, slogutil.WithBridgeLevel(slog.LevelError)), "", log.LstdFlags|log.Lmicroseconds)
+	stdLogger.Print("first message")
+	stdLogger.Print("second message")
+
+	for _, message := range []string{"first message", "second message"} {
+		query := slogmem.RecordQuery{Level: slog.LevelError, Message: message, Attrs: map[string]slog.Value{}}
+		if ok, diff := logs.ContainsExact(query); !ok {
+			t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+		}
+	}
+
+	if got := logs.Len(); got != 2 {
+		t.Errorf("logs.Len() want: 2, got: %d", got)
+	}
+
+	_, _ = slogutil.NewStdLogWriter(logger).Write([]byte("below the level"))
+
+	if got := logs.ByMessage("below the level").Len(); got != 0 {
+		t.Errorf("expected records below the logger's level to be dropped, got: %d", got)
+	}
+
+	for record := range logs.All() {
+		if strings.Contains(record.Message, "/") {
+			t.Errorf("expected the date and time prefix to be removed, got: %s", record.Message)
+		}
+	}
+}
+
+func TestNewStdLogWriterWritesAMultiLineMessageAsASingleRecord(t *testing.T) {
+	t.Parallel()
+
+	logger, logs := slogutil.NewInMemoryLogger(slog.LevelInfo)
+
+	stdLogger := log.New(slogutil.NewStdLogWriter(logger), "", log.LstdFlags)
+	stdLogger.Print("running jobs:\n12:00:00 job started\n  indented detail\n")
+
+	query := slogmem.RecordQuery{
+		Level:   slog.LevelInfo,
+		Message: "running jobs:\n12:00:00 job started\n  indented detail",
+		Attrs:   map[string]slog.Value{},
+	}
+
+	if ok, diff := logs.ContainsExact(query); !ok {
+		t.Errorf("expected logged records to contain: %+v, got: %s", query, diff)
+	}
+
+	if got := logs.Len(); got != 1 {
+		t.Errorf("logs.Len() want: 1, got: %d", got)
+	}
+}